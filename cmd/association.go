@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"hashcat-auto/config"
+	"hashcat-auto/utils"
+	"path/filepath"
+
+	"github.com/fatih/color"
+)
+
+// hashcatErrorExitCode is the exit status hashcat uses for errors such as an unsupported attack mode.
+const hashcatErrorExitCode = 255
+
+// runAssociationAttack runs hashcat's association attack (-a 9) with rules, pairing each hash with hints
+// taken from its own account. It returns false if hashcat rejects the first round, which is how an
+// unsupported hash mode shows; a hashcat that was killed or could not start is an error.
+func runAssociationAttack(hashcatPath, hashcatMode, hashlist, rulesFull, timestamp string, lineHints [][]string) (bool, error) {
+	rounds := utils.AssociationRounds(lineHints)
+	for i, hints := range rounds {
+		hintFile := filepath.Join(config.CacheDir, fmt.Sprintf("association_hints_%s_%d.txt", timestamp, i+1))
+		if err := utils.WriteToFile(hintFile, hints); err != nil {
+			return false, fmt.Errorf("error writing association hints to file: %w", err)
+		}
+
+		color.Yellow("Running association attack round %d of %d...", i+1, len(rounds))
+		hashcatCommand := []string{"-a", "9", "-m", hashcatMode, hashlist, hintFile, "-r", rulesFull, "--status", "--status-timer", "30"}
+		fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
+		err := runHashcat(hashcatPath, hashcatCommand) // Hashcat returns 1 when exhausted, only 255 is a hard failure
+		switch code := utils.ExitCode(err); {
+		case code == hashcatErrorExitCode && i == 0:
+			// Hashcat rejects -a 9 up front for modes it does not support
+			return false, nil
+		case code == hashcatErrorExitCode:
+			color.Yellow("Association attack round %d failed, continuing with the next round.", i+1)
+		case code == -1:
+			return false, fmt.Errorf("association attack round %d did not complete: %w", i+1, err)
+		}
+	}
+	return true, nil
+}
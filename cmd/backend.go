@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"hashcat-auto/config"
+	"hashcat-auto/cracker"
+	"hashcat-auto/utils"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+)
+
+// GoBackend is the hashcat path that selects the built-in Go CPU backend instead of a hashcat binary.
+const GoBackend = "go-backend"
+
+// goBackendPotfile is the potfile the Go backend uses when a step does not pass --potfile-path.
+func goBackendPotfile() string {
+	return filepath.Join(config.CacheDir, "go_backend.potfile")
+}
+
+// withEngagementPotfile points hashcat at the engagement's potfile unless the command names one, so that
+// cracks and --show results never mix with other engagements.
+func withEngagementPotfile(args []string) []string {
+	if config.EngagementPotfile == "" {
+		return args
+	}
+	for _, arg := range args {
+		if arg == "--potfile-path" {
+			return args
+		}
+	}
+	return append(append([]string(nil), args...), "--potfile-path", config.EngagementPotfile)
+}
+
+// runHashcat runs a hashcat command with its output in the terminal, using the Go backend when selected.
+func runHashcat(hashcatPath string, args []string) error {
+	args = withEngagementPotfile(args)
+	if hashcatPath != GoBackend {
+		return utils.RunCommand(hashcatPath, args)
+	}
+	stdout, flush := utils.TerminalOutput()
+	defer flush()
+	if err := cracker.Run(args, cracker.Options{Potfile: goBackendPotfile(), Stdout: stdout}); err != nil {
+		color.Red("Go backend: %v", err)
+		return err
+	}
+	return nil
+}
+
+// runHashcatToFile runs a hashcat command with its output redirected to a file, using the Go backend when selected.
+func runHashcatToFile(hashcatPath string, args []string, outputFile string) error {
+	args = withEngagementPotfile(args)
+	if hashcatPath != GoBackend {
+		return utils.RunCommandToFile(hashcatPath, args, outputFile)
+	}
+	output, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("failed to create output file %s: %w", outputFile, err)
+	}
+	defer output.Close()
+	if err := cracker.Run(args, cracker.Options{Potfile: goBackendPotfile(), Stdout: output}); err != nil {
+		color.Red("Go backend: %v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"hashcat-auto/config"
+	"hashcat-auto/utils"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// RunCorpus implements the `corpus` subcommand for inspecting the found-passwords corpus and adding
+// the plaintexts of earlier runs to it.
+func RunCorpus(args []string) error {
+	if len(args) == 0 || (args[0] != "show" && args[0] != "add") {
+		return fmt.Errorf("usage: corpus show [--top N] [--redact MODE] | corpus add <cracked file>...")
+	}
+	flags := flag.NewFlagSet("corpus "+args[0], flag.ExitOnError)
+	corpusFile := flags.String("corpus", config.CorpusFile, "Path to the found-passwords corpus")
+	top := flags.Int("top", 20, "Number of passwords to show")
+	redact := flags.String("redact", "full", "Password redaction for show: full, partial or hash")
+	flags.Parse(args[1:])
+
+	redaction, err := utils.ParseRedactionMode(*redact)
+	if err != nil {
+		return err
+	}
+
+	corpus, err := utils.LoadCorpus(*corpusFile)
+	if err != nil {
+		return err
+	}
+
+	if args[0] == "add" {
+		if flags.NArg() == 0 {
+			flags.Usage()
+			return fmt.Errorf("at least one cracked file is required")
+		}
+		for _, filename := range flags.Args() {
+			passwords, err := utils.ReadCrackedPasswords(filename)
+			if err != nil {
+				return fmt.Errorf("error reading cracked passwords: %w", err)
+			}
+			info, err := os.Stat(filename)
+			if err != nil {
+				return fmt.Errorf("failed to stat %s: %w", filename, err)
+			}
+			source, err := utils.FileSHA256(filename)
+			if err != nil {
+				return err
+			}
+			added := corpus.Add(passwords, info.ModTime(), source)
+			color.Green("%s: %d passwords, %d new to the corpus.", filename, len(passwords), added)
+		}
+		return corpus.Save()
+	}
+
+	entries := corpus.Entries()
+	color.Green("%d passwords in %s", len(entries), *corpusFile)
+	if *top < len(entries) {
+		entries = entries[:*top]
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "HASHLISTS\tLAST SEEN\tPASSWORD")
+	for _, entry := range entries {
+		fmt.Fprintf(writer, "%d\t%s\t%s\n", entry.Count, entry.LastSeen.Format(time.DateOnly), redaction.Redact(entry.Password, ""))
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hashcat-auto/config"
+	"hashcat-auto/report"
+	"hashcat-auto/rundb"
+	"hashcat-auto/utils"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// maxDiffListed caps how many accounts of each diff category are printed.
+const maxDiffListed = 25
+
+// RunDiff implements the `diff` subcommand, comparing the accounts of two runs or two hashlists.
+func RunDiff(args []string) error {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	oldCracked := flags.String("old-cracked", "", "Cracked file or potfile for the previous hashlist")
+	newCracked := flags.String("new-cracked", "", "Cracked file or potfile for the current hashlist")
+	dbPath := flags.String("db", config.HistoryDB, "Path to the run history database, for comparing run IDs")
+	output := flags.String("output", "", "Also write the comparison as JSON to this file")
+	redact := flags.String("redact", "full", "Password redaction in terminal and JSON output: full, partial or hash")
+	flags.Parse(args)
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf("usage: diff [flags] <previous run ID or hashlist> <current run ID or hashlist>")
+	}
+	redaction, err := utils.ParseRedactionMode(*redact)
+	if err != nil {
+		return err
+	}
+
+	old, err := loadSnapshot(flags.Arg(0), *oldCracked, *dbPath)
+	if err != nil {
+		return err
+	}
+	current, err := loadSnapshot(flags.Arg(1), *newCracked, *dbPath)
+	if err != nil {
+		return err
+	}
+	stats := report.CompareAssessments(old, current).Redacted(redaction)
+	printDiff(stats)
+
+	if *output != "" {
+		data, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode comparison: %w", err)
+		}
+		if err := os.WriteFile(*output, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", *output, err)
+		}
+		color.Green("Comparison written: %s", *output)
+	}
+	return nil
+}
+
+// loadSnapshot loads the accounts of an assessment from a hashlist file with an optional cracked file,
+// or otherwise from a run in the run history. History entries are left out.
+func loadSnapshot(source, crackedFile, dbPath string) ([]report.AccountSnapshot, error) {
+	if _, err := os.Stat(source); err == nil {
+		entries, err := utils.ParseHashlist(source)
+		if err != nil {
+			return nil, fmt.Errorf("error reading hashlist: %w", err)
+		}
+		var crackedLines []string
+		if crackedFile != "" {
+			if crackedLines, err = utils.ReadLines(crackedFile); err != nil {
+				return nil, fmt.Errorf("error reading cracked passwords: %w", err)
+			}
+		} else {
+			color.Yellow("No cracked file given for %s, treating every account as not cracked.", source)
+		}
+		cracked := utils.MatchCracked(entries, crackedLines)
+
+		var snapshots []report.AccountSnapshot
+		for _, entry := range entries {
+			if entry.Account.Kind == utils.AccountHistory {
+				continue
+			}
+			password, ok := cracked[entry.Hash]
+			snapshots = append(snapshots, report.AccountSnapshot{Account: entry.Account.Raw, Hash: entry.Hash, Password: password, Cracked: ok})
+		}
+		return snapshots, nil
+	}
+
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s is neither a hashlist nor a run, no run history at %s", source, dbPath)
+	}
+	db, err := rundb.Open(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	record, err := db.Record(source)
+	if err != nil {
+		return nil, fmt.Errorf("%s is neither a hashlist nor a recorded run: %w", source, err)
+	}
+
+	cracked := make(map[string]string)
+	for _, crack := range record.Cracks {
+		cracked[crack.Hash] = crack.Password
+	}
+	var snapshots []report.AccountSnapshot
+	for _, account := range record.Accounts {
+		if utils.NormalizeUsername(account.Name).Kind == utils.AccountHistory {
+			continue
+		}
+		password, ok := cracked[utils.NormalizeHash(account.Hash)]
+		snapshots = append(snapshots, report.AccountSnapshot{Account: account.Name, Hash: account.Hash, Password: password, Cracked: ok})
+	}
+	return snapshots, nil
+}
+
+// printDiff prints the comparison summary and the accounts in each category.
+func printDiff(stats report.DiffStats) {
+	color.Green("Previous: %d accounts, %d cracked. Current: %d accounts, %d cracked.",
+		stats.OldAccounts, stats.OldCracked, stats.NewAccounts, stats.NewCracked)
+
+	printDiffAccounts("Newly cracked after a hash change", stats.NewlyCracked, func(a report.DiffAccount) string { return a.NewPassword })
+	printDiffAccounts("No longer cracked (hash changed)", stats.NoLongerCracked, func(a report.DiffAccount) string { return "was " + a.OldPassword })
+	printDiffAccounts("Hash changed but cracked again", stats.CrackedAgain, func(a report.DiffAccount) string {
+		return a.OldPassword + " -> " + a.NewPassword
+	})
+
+	color.Yellow("\nUnchanged hash since the previous assessment: %d accounts, %d with a known password", len(stats.Unchanged), stats.UnchangedKnown)
+	var known []report.DiffAccount
+	for _, account := range stats.Unchanged {
+		if account.OldCracked || account.NewCracked {
+			known = append(known, account)
+		}
+	}
+	printAccountList(known, func(a report.DiffAccount) string {
+		switch {
+		case !a.OldCracked:
+			return a.NewPassword + " (first cracked now)"
+		case !a.NewCracked:
+			return a.OldPassword + " (cracked before)"
+		default:
+			return a.NewPassword
+		}
+	})
+
+	if len(stats.Added) > 0 || len(stats.Removed) > 0 {
+		color.Yellow("\n%d accounts added, %d removed.", len(stats.Added), len(stats.Removed))
+	}
+}
+
+// printDiffAccounts prints one category of the comparison.
+func printDiffAccounts(title string, accounts []report.DiffAccount, detail func(report.DiffAccount) string) {
+	color.Yellow("\n%s: %d", title, len(accounts))
+	printAccountList(accounts, detail)
+}
+
+// printAccountList prints accounts with a detail, up to maxDiffListed.
+func printAccountList(accounts []report.DiffAccount, detail func(report.DiffAccount) string) {
+	for i, account := range accounts {
+		if i == maxDiffListed {
+			fmt.Printf("  ... and %d more\n", len(accounts)-maxDiffListed)
+			break
+		}
+		if text := strings.TrimSpace(detail(account)); text != "" {
+			fmt.Printf("  %s: %s\n", account.Account, text)
+		} else {
+			fmt.Printf("  %s\n", account.Account)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hashcat-auto/config"
+	"hashcat-auto/utils"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// ownedPrincipal is a cracked account in the owned-principals JSON export.
+type ownedPrincipal struct {
+	Name           string              `json:"name"`
+	SAMAccountName string              `json:"samaccountname"`
+	Domain         string              `json:"domain"`
+	Type           string              `json:"type"`
+	Hash           string              `json:"hash"`
+	Password       string              `json:"password"`
+	AD             *utils.ADAttributes `json:"ad,omitempty"`
+}
+
+// RunExport implements the `export` subcommand, writing cracked accounts as BloodHound owned principals.
+func RunExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	cracked := flags.String("cracked", "", "Path to the cracked file or potfile (REQUIRED)")
+	hashlist := flags.String("hashlist", "", "Path to the hashlist with DOMAIN\\user or user@domain accounts (REQUIRED)")
+	domain := flags.String("domain", "", "DNS domain for NetBIOS names: corp.local, or NETBIOS=fqdn pairs such as CORP=corp.local,LAB=lab.local")
+	adFiles := flags.String("ad", "", "Comma-separated ldapdomaindump domain_users.json or LDIF files to add account attributes")
+	output := flags.String("output", "", "Output path without extension (default: owned_<timestamp> in the cache directory)")
+	redact := flags.String("redact", "full", "Password redaction in the JSON export: full, partial or hash")
+	flags.Parse(args)
+
+	if *cracked == "" || *hashlist == "" {
+		flags.Usage()
+		return fmt.Errorf("--cracked and --hashlist are required")
+	}
+	redaction, err := utils.ParseRedactionMode(*redact)
+	if err != nil {
+		return err
+	}
+
+	entries, err := utils.ParseHashlist(*hashlist)
+	if err != nil {
+		return fmt.Errorf("error reading hashlist: %w", err)
+	}
+	crackedLines, err := utils.ReadLines(*cracked)
+	if err != nil {
+		return fmt.Errorf("error reading cracked passwords: %w", err)
+	}
+	if *adFiles != "" {
+		attributes, err := utils.LoadADAttributes(SplitList(*adFiles))
+		if err != nil {
+			return fmt.Errorf("error loading AD attributes: %w", err)
+		}
+		utils.AttachADAttributes(entries, attributes)
+	}
+
+	principals := ownedPrincipals(entries, utils.MatchCracked(entries, crackedLines), utils.ParseDomainMap(*domain), redaction)
+	if len(principals) == 0 {
+		color.Yellow("No cracked user or computer accounts to export.")
+		return nil
+	}
+
+	outputBase := *output
+	if outputBase == "" {
+		outputBase = filepath.Join(config.CacheDir, fmt.Sprintf("owned_%s", time.Now().Format("20060102_150405")))
+	}
+	if err := os.MkdirAll(filepath.Dir(outputBase), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory for %s: %w", outputBase, err)
+	}
+
+	names := make([]string, len(principals))
+	for i, principal := range principals {
+		names[i] = principal.Name
+	}
+	if err := utils.WriteToFile(outputBase+".txt", names); err != nil {
+		return fmt.Errorf("error writing owned principals: %w", err)
+	}
+
+	content, err := json.MarshalIndent(principals, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding owned principals: %w", err)
+	}
+	if err := os.WriteFile(outputBase+".json", append(content, '\n'), 0644); err != nil {
+		return fmt.Errorf("error writing owned principals: %w", err)
+	}
+
+	color.Green("Exported %d owned principals: %s.txt, %s.json", len(principals), outputBase, outputBase)
+	return nil
+}
+
+// ownedPrincipals builds the owned-principal list from the cracked user and computer accounts, one per principal,
+// masking passwords per mode.
+func ownedPrincipals(entries []utils.HashlistEntry, crackedByHash map[string]string, domains map[string]string, mode utils.RedactionMode) []ownedPrincipal {
+	seen := make(map[string]struct{})
+	var principals []ownedPrincipal
+	missingDomain := 0
+	for _, entry := range entries {
+		password, ok := crackedByHash[entry.Hash]
+		if !ok {
+			continue
+		}
+
+		principalType := "User"
+		switch entry.Account.Kind {
+		case utils.AccountUser:
+		case utils.AccountMachine:
+			principalType = "Computer"
+		default:
+			continue
+		}
+
+		name := entry.Account.PrincipalName(domains)
+		if _, duplicate := seen[name]; duplicate {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		fqdn := entry.Account.FQDN(domains)
+		if !strings.Contains(fqdn, ".") {
+			missingDomain++
+		}
+		principals = append(principals, ownedPrincipal{
+			Name:           name,
+			SAMAccountName: entry.Account.Username,
+			Domain:         strings.ToUpper(fqdn),
+			Type:           principalType,
+			Hash:           entry.Hash,
+			Password:       mode.Redact(password, entry.Hash),
+			AD:             entry.AD,
+		})
+	}
+
+	if missingDomain > 0 {
+		color.Yellow("%d principals have no DNS domain; map NetBIOS names with --domain, e.g. --domain CORP=corp.local.", missingDomain)
+	}
+	sort.Slice(principals, func(i, j int) bool { return principals[i].Name < principals[j].Name })
+	return principals
+}
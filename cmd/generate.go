@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"hashcat-auto/config"
+	"hashcat-auto/utils"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// DefaultYearRange returns the default year range for context wordlists: three years back to next year.
+func DefaultYearRange() string {
+	year := time.Now().Year()
+	return fmt.Sprintf("%d-%d", year-3, year+1)
+}
+
+// NewOrgContext builds an organisation context from command-line values, where keywords and
+// languages are comma-separated and years is a range such as "2020-2025".
+func NewOrgContext(company, city, keywords, years, languages string) (utils.OrgContext, error) {
+	startYear, endYear, err := utils.ParseYearRange(years)
+	if err != nil {
+		return utils.OrgContext{}, fmt.Errorf("invalid --years: %w", err)
+	}
+	return utils.OrgContext{
+		Company:   strings.TrimSpace(company),
+		City:      strings.TrimSpace(city),
+		Keywords:  SplitList(keywords),
+		StartYear: startYear,
+		EndYear:   endYear,
+		Languages: SplitList(languages),
+	}, nil
+}
+
+// SplitList splits a comma-separated flag value, dropping empty entries.
+func SplitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// writeContextWordlist generates the organisation-context candidates and writes them to outputFile.
+func writeContextWordlist(ctx utils.OrgContext, outputFile string) error {
+	candidates, err := utils.GenerateContextWordlist(ctx)
+	if err != nil {
+		return fmt.Errorf("error generating context wordlist: %w", err)
+	}
+	if err := utils.WriteToFile(outputFile, candidates); err != nil {
+		return fmt.Errorf("error writing context wordlist to file: %w", err)
+	}
+	color.Green("Generated %d context candidates: %s", len(candidates), outputFile)
+	return nil
+}
+
+// RunGenerate implements the `generate` subcommand, writing an organisation-context wordlist.
+func RunGenerate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	company := flags.String("company", "", "Company name")
+	city := flags.String("city", "", "City or region")
+	keywords := flags.String("keywords", "", "Comma-separated extra keywords (products, brands, sports teams)")
+	years := flags.String("years", DefaultYearRange(), "Year range to combine with words, e.g. 2020-2025")
+	languages := flags.String("languages", "en", "Comma-separated languages for seasons and months ("+strings.Join(utils.ContextLanguages(), ", ")+")")
+	output := flags.String("output", "", "Output file (default: context_wordlist_<timestamp>.txt in the cache directory)")
+	flags.Parse(args)
+
+	ctx, err := NewOrgContext(*company, *city, *keywords, *years, *languages)
+	if err != nil {
+		return err
+	}
+	if ctx.Empty() {
+		return fmt.Errorf("at least one of --company, --city or --keywords is required")
+	}
+
+	outputFile := *output
+	if outputFile == "" {
+		timestamp := time.Now().Format("20060102_150405")
+		outputFile = filepath.Join(config.CacheDir, fmt.Sprintf("context_wordlist_%s.txt", timestamp))
+	}
+	return writeContextWordlist(ctx, outputFile)
+}
+
+// yearsLabel formats a context's year range for terminal output.
+func yearsLabel(ctx utils.OrgContext) string {
+	if ctx.StartYear == ctx.EndYear {
+		return strconv.Itoa(ctx.StartYear)
+	}
+	return fmt.Sprintf("%d-%d", ctx.StartYear, ctx.EndYear)
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"hashcat-auto/config"
 	"hashcat-auto/utils"
@@ -31,10 +32,17 @@ func validateEnvironment(hashcatPath string) error {
 	return nil
 }
 
-func getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile string, step int) error {
+func getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, crackLogFile string, blank []utils.HashlistEntry, step string) error {
 	color.Yellow("Extracting passwords for stats using --show...")
 
 	currentCount, err := utils.CountLines(cumulativeCrackedFile)
+	if errors.Is(err, os.ErrNotExist) {
+		currentCount, err = 0, nil // Nothing extracted yet
+	}
+	if err != nil {
+		return err
+	}
+	previousLines, err := utils.ReadLines(cumulativeCrackedFile)
 	if err != nil {
 		return err
 	}
@@ -42,7 +50,10 @@ func getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile,
 	var hashcatCommand []string
 	hashcatCommand = []string{"-m", hashcatMode, hashlist, "--show"}
 	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
-	_ = utils.RunCommandToFile(hashcatPath, hashcatCommand, cumulativeCrackedFile) // Ignore error as Hashcat may return 1 even on success
+	_ = runHashcatToFile(hashcatPath, hashcatCommand, cumulativeCrackedFile) // Ignore error as Hashcat may return 1 even on success
+	if _, err := appendPreflightCracked(cumulativeCrackedFile, blank); err != nil {
+		return err
+	}
 
 	newCount, err := utils.CountLines(cumulativeCrackedFile)
 	if err != nil {
@@ -51,29 +62,226 @@ func getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile,
 
 	color.Red("Extracted %d new passwords for stats.", newCount-currentCount)
 
-	statsMessage := fmt.Sprintf("Extracted %d new passwords for step %d.", newCount-currentCount, step)
+	statsMessage := fmt.Sprintf("Extracted %d new passwords for step %s.", newCount-currentCount, step)
 
 	if err := utils.AppendToFile(cumulativeCrackedStatsFile, []string{statsMessage}); err != nil {
 		return fmt.Errorf("error writing cracked passwords to file: %w", err)
 	}
 
+	// Record which lines this step cracked, for time-to-crack analysis
+	currentLines, err := utils.ReadLines(cumulativeCrackedFile)
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]struct{}, len(previousLines))
+	for _, line := range previousLines {
+		seen[line] = struct{}{}
+	}
+	var newLines []string
+	for _, line := range currentLines {
+		if _, ok := seen[line]; !ok {
+			newLines = append(newLines, line)
+		}
+	}
+	if err := utils.AppendCrackLog(crackLogFile, step, newLines); err != nil {
+		return fmt.Errorf("error writing crack log: %w", err)
+	}
+
+	return nil
+}
+
+// estimateAttack prints the size of a wordlist and the number of guesses its rules expand it to. Line
+// counts are cached in the cache directory, so multi-GB wordlists are only read once. The estimate is
+// informational: problems are reported as warnings.
+func estimateAttack(wordlist string, rules ...string) {
+	words, err := utils.CountLinesCached(wordlist, filepath.Join(config.CacheDir, "line_counts.json"))
+	if err != nil {
+		color.Yellow("Could not size wordlist: %v", err)
+		return
+	}
+	if len(rules) == 0 {
+		color.Yellow("%s: %d candidates.", wordlist, words)
+		return
+	}
+	guesses := words
+	for _, rule := range rules {
+		count, err := utils.CountRules(rule)
+		if err != nil {
+			color.Yellow("Could not size rule file: %v", err)
+			return
+		}
+		guesses *= count
+	}
+	color.Yellow("%s: %d candidates, about %d guesses with rules.", wordlist, words, guesses)
+}
+
+// runHistoryPrediction cracks accounts whose current password is unknown with successors of the most recent
+// password cracked from their secretsdump `_historyN` entries. It does nothing if the hashlist has no history.
+func runHistoryPrediction(hashcatPath, hashcatMode, hashlist, timestamp string) error {
+	entries, err := utils.ParseHashlist(hashlist)
+	if err != nil {
+		return fmt.Errorf("error reading hashlist: %w", err)
+	}
+	histories := utils.LinkPasswordHistory(entries)
+	if len(histories) == 0 {
+		return nil
+	}
+
+	color.Yellow("Predicting current passwords from password history of %d accounts...", len(histories))
+	tempCrackedFile := filepath.Join(config.CacheDir, fmt.Sprintf("temp_history_cracked_%s.txt", timestamp))
+	hashcatCommand := []string{"-m", hashcatMode, hashlist, "--show"}
+	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
+	_ = runHashcatToFile(hashcatPath, hashcatCommand, tempCrackedFile) // Ignore error as Hashcat may return 1 even on success
+	crackedLines, err := utils.ReadLines(tempCrackedFile)
+	if err != nil {
+		return fmt.Errorf("error reading cracked passwords: %w", err)
+	}
+
+	candidates := utils.HistoryCandidates(histories, utils.MatchCracked(entries, crackedLines))
+	if len(candidates) == 0 {
+		color.Yellow("No cracked history passwords to predict from.")
+		return nil
+	}
+	for i, candidate := range candidates {
+		candidates[i] = utils.EncodeHexPlain(candidate) // Hashcat decodes $HEX[...] words
+	}
+	candidatesFile := filepath.Join(config.CacheDir, fmt.Sprintf("history_candidates_%s.txt", timestamp))
+	if err := utils.WriteToFile(candidatesFile, candidates); err != nil {
+		return fmt.Errorf("error writing history candidates to file: %w", err)
+	}
+	hashcatCommand = []string{"-a", "0", "-m", hashcatMode, hashlist, candidatesFile, "--status", "--status-timer", "30"}
+	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
+	_ = runHashcat(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
+	color.Green("History prediction processing completed (%d candidates).", len(candidates))
+	return nil
+}
+
+// runCorpusTop runs the top passwords of the found-passwords corpus as a straight wordlist attack.
+func runCorpusTop(hashcatPath, hashcatMode, hashlist, corpusFile string, top int, timestamp string) error {
+	corpus, err := utils.LoadCorpus(corpusFile)
+	if err != nil {
+		return err
+	}
+	candidates := corpus.Top(top)
+	for i, candidate := range candidates {
+		candidates[i] = utils.EncodeHexPlain(candidate) // Hashcat decodes $HEX[...] words
+	}
+	if len(candidates) == 0 {
+		color.Yellow("Found-passwords corpus %s is empty, skipping the corpus step.", corpusFile)
+		return nil
+	}
+
+	color.Yellow("Running the top %d passwords from the found-passwords corpus...", len(candidates))
+	candidatesFile := filepath.Join(config.CacheDir, fmt.Sprintf("corpus_top_%s.txt", timestamp))
+	if err := utils.WriteToFile(candidatesFile, candidates); err != nil {
+		return fmt.Errorf("error writing corpus candidates to file: %w", err)
+	}
+	hashcatCommand := []string{"-a", "0", "-m", hashcatMode, hashlist, candidatesFile, "--status", "--status-timer", "30"}
+	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
+	_ = runHashcat(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
+	color.Green("Corpus processing completed.")
+	return nil
+}
+
+// updateCorpus adds the plaintexts of a run's cumulative cracked file to the found-passwords corpus,
+// counting each password once per hashlist however often the hashlist is run.
+func updateCorpus(corpusFile, hashlist, cumulativeCrackedFile string) error {
+	corpus, err := utils.LoadCorpus(corpusFile)
+	if err != nil {
+		return err
+	}
+	passwords, err := utils.ReadCrackedPasswords(cumulativeCrackedFile)
+	if err != nil {
+		return fmt.Errorf("error reading cracked passwords: %w", err)
+	}
+	source, err := utils.FileSHA256(hashlist)
+	if err != nil {
+		return err
+	}
+	added := corpus.Add(passwords, time.Now(), source)
+	if err := corpus.Save(); err != nil {
+		return err
+	}
+	color.Green("Found-passwords corpus updated: %d new passwords, %d in total.", added, len(corpus.Entries()))
 	return nil
 }
 
-func ProcessHashcatTasks(hashlist, wordlist, potfile, clemRule, rulesFull, cewlURL, cewlWordlist, hashcatPath, hashcatMode, passphrases, passphraseRule1, passphraseRule2, dictionary string, enableAdditionalWordlists bool) error {
+// printSkippedAccounts reports how many machine, built-in and history accounts were left out of the username wordlist.
+func printSkippedAccounts(accounts []utils.Account) {
+	skipped := make(map[utils.AccountKind]int)
+	for _, account := range accounts {
+		if account.Kind != utils.AccountUser {
+			skipped[account.Kind]++
+		}
+	}
+	for _, kind := range []utils.AccountKind{utils.AccountMachine, utils.AccountBuiltin, utils.AccountHistory} {
+		if skipped[kind] > 0 {
+			color.Yellow("Skipping %d %s accounts for username candidates.", skipped[kind], kind)
+		}
+	}
+}
+
+func ProcessHashcatTasks(hashlist, wordlist, potfile, sharedPotfile, clemRule, rulesFull, cewlURL, cewlWordlist, hashcatPath, hashcatMode, passphrases, passphraseRule1, passphraseRule2, dictionary, hintsFile, groupFiles, extraSensitiveGroups, corpusFile string, corpusTop int, orgContext utils.OrgContext, redaction utils.RedactionMode, enableAdditionalWordlists bool) (err error) {
 
 	// Step 1: Validate hashlist
 	color.Yellow("Validating hashlist...")
 	if err := utils.ValidateFileExists(hashlist); err != nil {
 		return fmt.Errorf("hashlist validation failed: %w", err)
 	}
+	entries, err := utils.ParseHashlist(hashlist)
+	if err != nil {
+		return fmt.Errorf("error reading hashlist: %w", err)
+	}
+	if redaction != utils.RedactFull {
+		utils.SetTerminalRedaction(redaction, entries)
+		color.Yellow("Masking cracked passwords in terminal output (%s).", redaction)
+	}
 
 	timestamp := time.Now().Format("20060102_150405")
 
 	cumulativeCrackedFile := filepath.Join(config.CacheDir, fmt.Sprintf("cumulative_cracked_%s.txt", timestamp))
 	cumulativeCrackedStatsFile := filepath.Join(config.CacheDir, fmt.Sprintf("cumulative_cracked_stats_%s.txt", timestamp))
+	crackLogFile := filepath.Join(config.CacheDir, fmt.Sprintf("crack_log_%s.txt", timestamp))
+	started := time.Now()
+	if err := utils.StartCrackLog(crackLogFile, started); err != nil {
+		return fmt.Errorf("error creating crack log: %w", err)
+	}
+	defer func() {
+		recordRun(runInfo{
+			id:                    timestamp,
+			started:               started,
+			hashlist:              hashlist,
+			mode:                  hashcatMode,
+			backend:               hashcatPath,
+			entries:               entries,
+			crackLogFile:          crackLogFile,
+			cumulativeCrackedFile: cumulativeCrackedFile,
+		}, err)
+	}()
+
+	// Report hash-only findings and mark blank passwords as cracked before running hashcat
+	blank := runPreflight(entries, hashcatMode)
+	preflightLines, err := appendPreflightCracked(cumulativeCrackedFile, blank)
+	if err != nil {
+		return err
+	}
+	if err := utils.AppendCrackLog(crackLogFile, "preflight", preflightLines); err != nil {
+		return fmt.Errorf("error writing crack log: %w", err)
+	}
 
-	getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, 1)
+	if err := getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, crackLogFile, blank, "initial"); err != nil {
+		return err
+	}
+
+	// Step 1b: Try the most frequently found passwords across engagements first
+	if corpusFile != "" && corpusTop > 0 {
+		if err := runCorpusTop(hashcatPath, hashcatMode, hashlist, corpusFile, corpusTop, timestamp); err != nil {
+			return err
+		}
+		if err := getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, crackLogFile, blank, "corpus_top"); err != nil {
+			return err
+		}
+	}
 
 	// Step 2: Extract passwords using --show and process them
 	color.Yellow("Extracting passwords using --show...")
@@ -82,7 +290,7 @@ func ProcessHashcatTasks(hashlist, wordlist, potfile, clemRule, rulesFull, cewlU
 
 	hashcatCommand = []string{"-m", hashcatMode, hashlist, "--show"}
 	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
-	_ = utils.RunCommandToFile(hashcatPath, hashcatCommand, tempCrackedFile) // Ignore error as Hashcat may return 1 even on success
+	_ = runHashcatToFile(hashcatPath, hashcatCommand, tempCrackedFile) // Ignore error as Hashcat may return 1 even on success
 	passwords, err := utils.ExtractPasswords(tempCrackedFile)
 	if err != nil {
 		return fmt.Errorf("error processing cracked passwords: %w", err)
@@ -93,68 +301,144 @@ func ProcessHashcatTasks(hashlist, wordlist, potfile, clemRule, rulesFull, cewlU
 	}
 	hashcatCommand = []string{"-a", "0", "-m", hashcatMode, hashlist, passwordsFile, "-r", rulesFull, "--status", "--status-timer", "30"}
 	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
-	_ = utils.RunCommand(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
+	_ = runHashcat(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
 	color.Green("Cracked password processing completed.")
 
-	getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, 2)
+	if err := getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, crackLogFile, blank, "cracked_rules"); err != nil {
+		return err
+	}
 
 	// Step 3: Get passwords with custom potfile and process them
-	color.Yellow("Extracting passwords from custom potfile using --show...")
-	tempCrackedFile = filepath.Join(config.CacheDir, fmt.Sprintf("temp_custom_potfile_cracked_passwords_%s.txt", timestamp))
+	if potfile != "" {
+		color.Yellow("Extracting passwords from custom potfile using --show...")
+		tempCrackedFile = filepath.Join(config.CacheDir, fmt.Sprintf("temp_custom_potfile_cracked_passwords_%s.txt", timestamp))
 
-	hashcatCommand = []string{"-m", hashcatMode, hashlist, "--show", "--potfile-path", potfile}
-	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
-	_ = utils.RunCommandToFile(hashcatPath, hashcatCommand, tempCrackedFile) // Ignore error as Hashcat may return 1 even on success
-	passwords, err = utils.ExtractPasswords(tempCrackedFile)
-	if err != nil {
-		return fmt.Errorf("error processing cracked passwords: %w", err)
+		hashcatCommand = []string{"-m", hashcatMode, hashlist, "--show", "--potfile-path", potfile}
+		fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
+		_ = runHashcatToFile(hashcatPath, hashcatCommand, tempCrackedFile) // Ignore error as Hashcat may return 1 even on success
+		passwords, err = utils.ExtractPasswords(tempCrackedFile)
+		if err != nil {
+			return fmt.Errorf("error processing cracked passwords: %w", err)
+		}
+		passwordsFile = filepath.Join(config.CacheDir, fmt.Sprintf("custom_potfile_cracked_passwords_%s.txt", timestamp))
+		if err := utils.WriteToFile(passwordsFile, passwords); err != nil {
+			return fmt.Errorf("error writing cracked passwords to file: %w", err)
+		}
+		hashcatCommand = []string{"-a", "0", "-m", hashcatMode, hashlist, passwordsFile, "-r", rulesFull, "--status", "--status-timer", "30"}
+		fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
+		_ = runHashcat(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
+		color.Green("Cracked password from potfile processing completed.")
+
+		if err := getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, crackLogFile, blank, "custom_potfile"); err != nil {
+			return err
+		}
 	}
-	passwordsFile = filepath.Join(config.CacheDir, fmt.Sprintf("custom_potfile_cracked_passwords_%s.txt", timestamp))
-	if err := utils.WriteToFile(passwordsFile, passwords); err != nil {
-		return fmt.Errorf("error writing cracked passwords to file: %w", err)
+
+	// Step 3b: Use the shared potfile's plaintexts as a wordlist, never its hashes or --show results
+	if sharedPotfile != "" {
+		color.Yellow("Running plaintexts from the shared potfile as a wordlist with rules_full.rule...")
+		sharedWordlist := filepath.Join(config.CacheDir, fmt.Sprintf("shared_potfile_wordlist_%s.txt", timestamp))
+		count, err := writePotfileWordlist([]string{sharedPotfile}, sharedWordlist, 1)
+		if err != nil {
+			return fmt.Errorf("error writing shared potfile wordlist: %w", err)
+		}
+		hashcatCommand = []string{"-a", "0", "-m", hashcatMode, hashlist, sharedWordlist, "-r", rulesFull, "--status", "--status-timer", "30"}
+		fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
+		_ = runHashcat(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
+		color.Green("Shared potfile wordlist processing completed (%d plaintexts).", count)
+
+		if err := getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, crackLogFile, blank, "shared_potfile"); err != nil {
+			return err
+		}
 	}
-	hashcatCommand = []string{"-a", "0", "-m", hashcatMode, hashlist, passwordsFile, "-r", rulesFull, "--status", "--status-timer", "30"}
-	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
-	_ = utils.RunCommand(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
-	color.Green("Cracked password from potfile processing completed.")
 
-	getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, 3)
+	// Step 3c: Run the organisation-context wordlist with rules_full.rule
+	if !orgContext.Empty() {
+		color.Yellow("Generating organisation-context wordlist for years %s...", yearsLabel(orgContext))
+		contextWordlist := filepath.Join(config.CacheDir, fmt.Sprintf("context_wordlist_%s.txt", timestamp))
+		if err := writeContextWordlist(orgContext, contextWordlist); err != nil {
+			return err
+		}
+		hashcatCommand = []string{"-a", "0", "-m", hashcatMode, hashlist, contextWordlist, "-r", rulesFull, "--status", "--status-timer", "30"}
+		fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
+		_ = runHashcat(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
+		color.Green("Organisation-context processing completed.")
+
+		if err := getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, crackLogFile, blank, "org_context"); err != nil {
+			return err
+		}
+	}
 
 	// Step 4: Run rockyou.txt wordlist
 	color.Yellow("Running rockyou.txt wordlist...")
+	estimateAttack(wordlist)
 	hashcatCommand = []string{"-a", "0", "-m", hashcatMode, hashlist, wordlist, "--status", "--status-timer", "30"}
 	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
-	_ = utils.RunCommand(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
+	_ = runHashcat(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
 	color.Green("Wordlist processing completed.")
 
-	getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, 4)
+	if err := getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, crackLogFile, blank, "wordlist"); err != nil {
+		return err
+	}
 
 	// Step 5: Run rockyou.txt with clem9669_large.rule
 	color.Yellow("Running rockyou.txt with clem9669_large.rule...")
+	estimateAttack(wordlist, clemRule)
 	hashcatCommand = []string{"-a", "0", "-m", hashcatMode, hashlist, wordlist, "-r", clemRule, "--status", "--status-timer", "30"}
 	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
-	_ = utils.RunCommand(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
+	_ = runHashcat(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
 	color.Green("Rule-based processing completed.")
 
-	getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, 5)
+	if err := getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, crackLogFile, blank, "clem_rule"); err != nil {
+		return err
+	}
 
-	// Step 6: Extract usernames and run with rules_full.rule
-	color.Yellow("Extracting usernames and running with rules_full.rule...")
-	usernames, err := utils.ExtractUsernames(hashlist)
+	// Step 6: Run an association attack with each account's own username and imported hints
+	color.Yellow("Extracting usernames and running association attack with rules_full.rule...")
+	accounts, err := utils.ExtractAccounts(hashlist)
 	if err != nil {
 		return fmt.Errorf("error extracting usernames: %w", err)
 	}
 
-	usernameFile := filepath.Join(config.CacheDir, fmt.Sprintf("usernames_%s.txt", timestamp))
-	if err := utils.WriteToFile(usernameFile, usernames); err != nil {
-		return fmt.Errorf("error writing usernames to file: %w", err)
+	var importedHints map[string][]string
+	if hintsFile != "" {
+		importedHints, err = utils.LoadAccountHints(hintsFile)
+		if err != nil {
+			return fmt.Errorf("error loading account hints: %w", err)
+		}
+		color.Green("Loaded hints for %d accounts from %s", len(importedHints), hintsFile)
+	}
+
+	lineHints := make([][]string, len(accounts))
+	for i, account := range accounts {
+		lineHints[i] = utils.AccountHints(account, importedHints)
+	}
+	supported, err := runAssociationAttack(hashcatPath, hashcatMode, hashlist, rulesFull, timestamp, lineHints)
+	if err != nil {
+		return err
 	}
-	hashcatCommand = []string{"-a", "0", "-m", hashcatMode, hashlist, usernameFile, "-r", rulesFull, "--status", "--status-timer", "30"}
-	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
-	_ = utils.RunCommand(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
-	color.Green("Username-based processing completed.")
 
-	getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, 6)
+	if supported {
+		color.Green("Association attack processing completed.")
+	} else {
+		// Fall back to trying every username against every hash
+		color.Yellow("Association attack not supported for mode %s, running usernames with rules_full.rule...", hashcatMode)
+		printSkippedAccounts(accounts)
+		usernames := utils.UsernameCandidates(accounts)
+
+		usernameFile := filepath.Join(config.CacheDir, fmt.Sprintf("usernames_%s.txt", timestamp))
+		if err := utils.WriteToFile(usernameFile, usernames); err != nil {
+			return fmt.Errorf("error writing usernames to file: %w", err)
+		}
+		hashcatCommand = []string{"-a", "0", "-m", hashcatMode, hashlist, usernameFile, "-r", rulesFull, "--status", "--status-timer", "30"}
+		fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
+		_ = runHashcat(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
+		color.Green("Username-based processing completed.")
+	}
+
+	if err := getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, crackLogFile, blank, "association"); err != nil {
+		return err
+	}
 
 	// Step 7: Use CeWL to generate a wordlist and run with rules_full.rule
 	if cewlURL != "" {
@@ -185,26 +469,35 @@ func ProcessHashcatTasks(hashlist, wordlist, potfile, clemRule, rulesFull, cewlU
 		// Use the cleaned wordlist with Hashcat
 		hashcatCommand := []string{"-a", "0", "-m", hashcatMode, hashlist, cleanedWordlist, "-r", rulesFull, "--status", "--status-timer", "30"}
 		fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
-		_ = utils.RunCommand(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
+		_ = runHashcat(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
 		color.Green("CeWL-based processing completed.")
 
-		getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, 7)
+		if err := getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, crackLogFile, blank, "cewl"); err != nil {
+			return err
+		}
 	}
 
 	// Step 8: Process passphrases with two rules
 	color.Yellow("Processing passphrases with two rules...")
 	hashcatCommand = []string{"-a", "0", "-m", hashcatMode, hashlist, passphrases, "-r", passphraseRule1, "-r", passphraseRule2, "--status", "--status-timer", "30"}
 	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
-	_ = utils.RunCommand(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
+	_ = runHashcat(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
 	color.Green("Passphrase processing completed.")
 
 	// Step 9: Extract and process additional wordlists
-	if enableAdditionalWordlists {
+	if enableAdditionalWordlists && hashcatPath == GoBackend {
+		color.Yellow("Skipping additional wordlists: they are streamed into a Hashcat binary, which the Go backend does not use.")
+	} else if enableAdditionalWordlists {
 		color.Yellow("Processing additional wordlists with Hashcat...")
+		// The pipelines bypass runHashcat, so they are pointed at the engagement potfile here
+		potfileArgs := ""
+		if extra := withEngagementPotfile(nil); len(extra) > 0 {
+			potfileArgs = " " + strings.Join(extra, " ")
+		}
 		extraWordlistCommands := []string{
-			"7z x -so /media/extra2/Wordlists/all_in_one.txt.7z | /media/extra/Work/tools/hashcat-6.2.6/hashcat.bin -a 0 -m " + hashcatMode + " " + hashlist,
-			"7z x -so /media/extra2/Wordlists/hashmob.net_2024-12-01.found.7z | /media/extra/Work/tools/hashcat-6.2.6/hashcat.bin -a 0 -m " + hashcatMode + " " + hashlist,
-			"bzip2 -dc /media/extra2/Wordlists/rockyou2024.txt.bz2 | /media/extra/Work/tools/hashcat-6.2.6/hashcat.bin -a 0 -m " + hashcatMode + " " + hashlist,
+			"7z x -so /media/extra2/Wordlists/all_in_one.txt.7z | /media/extra/Work/tools/hashcat-6.2.6/hashcat.bin -a 0 -m " + hashcatMode + " " + hashlist + potfileArgs,
+			"7z x -so /media/extra2/Wordlists/hashmob.net_2024-12-01.found.7z | /media/extra/Work/tools/hashcat-6.2.6/hashcat.bin -a 0 -m " + hashcatMode + " " + hashlist + potfileArgs,
+			"bzip2 -dc /media/extra2/Wordlists/rockyou2024.txt.bz2 | /media/extra/Work/tools/hashcat-6.2.6/hashcat.bin -a 0 -m " + hashcatMode + " " + hashlist + potfileArgs,
 		}
 
 		for _, cmd := range extraWordlistCommands {
@@ -213,23 +506,30 @@ func ProcessHashcatTasks(hashlist, wordlist, potfile, clemRule, rulesFull, cewlU
 		}
 		color.Green("Additional wordlists processed.")
 
-		getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, 8)
+		if err := getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, crackLogFile, blank, "additional_wordlists"); err != nil {
+			return err
+		}
 	}
 
 	// Step 9: Process dictionary with rules_full.rule
 	color.Yellow("Running dictonary with rules_full.rule...")
 	hashcatCommand = []string{"-a", "0", "-m", hashcatMode, hashlist, dictionary, "-r", rulesFull, "--status", "--status-timer", "30"}
 	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
-	_ = utils.RunCommand(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
+	_ = runHashcat(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
 	color.Green("Dictionary processing completed.")
 
+	// Step 9b: Predict current passwords from cracked password history
+	if err := runHistoryPrediction(hashcatPath, hashcatMode, hashlist, timestamp); err != nil {
+		return err
+	}
+
 	// Step 10: Extract passwords using --show and process them
 	color.Yellow("Extracting passwords using --show...")
 	tempCrackedFile = filepath.Join(config.CacheDir, fmt.Sprintf("temp_cracked_passwords_%s.txt", timestamp))
 
 	hashcatCommand = []string{"-m", hashcatMode, hashlist, "--show"}
 	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
-	_ = utils.RunCommandToFile(hashcatPath, hashcatCommand, tempCrackedFile) // Ignore error as Hashcat may return 1 even on success
+	_ = runHashcatToFile(hashcatPath, hashcatCommand, tempCrackedFile) // Ignore error as Hashcat may return 1 even on success
 	passwords, err = utils.ExtractPasswords(tempCrackedFile)
 	if err != nil {
 		return fmt.Errorf("error processing cracked passwords: %w", err)
@@ -240,10 +540,32 @@ func ProcessHashcatTasks(hashlist, wordlist, potfile, clemRule, rulesFull, cewlU
 	}
 	hashcatCommand = []string{"-a", "0", "-m", hashcatMode, hashlist, passwordsFile, "-r", rulesFull, "--status", "--status-timer", "30"}
 	fmt.Printf("DEBUG: Hashcat command: %s %v\n", hashcatPath, hashcatCommand)
-	_ = utils.RunCommand(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
+	_ = runHashcat(hashcatPath, hashcatCommand) // Ignore error as Hashcat may return 1 even on success
 	color.Green("Cracked password processing completed.")
 
-	getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, 9)
+	if err := getPasswordStats(hashcatMode, hashcatPath, hashlist, cumulativeCrackedFile, cumulativeCrackedStatsFile, crackLogFile, blank, "final_cracked_rules"); err != nil {
+		return err
+	}
+
+	// Summarise cracked accounts in sensitive groups
+	if groupFiles != "" {
+		crackedLines, err := utils.ReadLines(cumulativeCrackedFile)
+		if err != nil {
+			return fmt.Errorf("error reading cracked passwords: %w", err)
+		}
+		privileged, err := analyzePrivileged(entries, utils.MatchCracked(entries, crackedLines), SplitList(groupFiles), sensitiveGroups(extraSensitiveGroups))
+		if err != nil {
+			return err
+		}
+		printPrivilegedSummary(privileged, redaction)
+	}
+
+	// Record this run's plaintexts in the found-passwords corpus
+	if corpusFile != "" {
+		if err := updateCorpus(corpusFile, hashlist, cumulativeCrackedFile); err != nil {
+			return err
+		}
+	}
 
 	color.Green("All steps completed successfully.")
 	return nil
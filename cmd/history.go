@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"hashcat-auto/config"
+	"hashcat-auto/rundb"
+	"hashcat-auto/utils"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// runInfo describes a pipeline run for the run history.
+type runInfo struct {
+	id                    string
+	started               time.Time
+	hashlist              string
+	mode                  string
+	backend               string
+	entries               []utils.HashlistEntry
+	crackLogFile          string
+	cumulativeCrackedFile string
+}
+
+// recordRun stores a finished or failed run in the run history. Recording problems are reported but
+// never fail the run.
+func recordRun(info runInfo, runErr error) {
+	if config.HistoryDB == "" {
+		return
+	}
+	record, err := buildRunRecord(info, runErr)
+	if err == nil {
+		err = saveRunRecord(record)
+	}
+	if err != nil {
+		color.Yellow("Run history not updated: %v", err)
+		return
+	}
+	color.Green("Run %s recorded in %s", info.id, config.HistoryDB)
+}
+
+// saveRunRecord writes a run to the run history database.
+func saveRunRecord(record rundb.RunRecord) error {
+	db, err := rundb.Open(config.HistoryDB)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.SaveRun(record)
+}
+
+// buildRunRecord collects a run's accounts, steps and cracks from its hashlist, crack log and cumulative
+// cracked file.
+func buildRunRecord(info runInfo, runErr error) (rundb.RunRecord, error) {
+	run := rundb.Run{
+		ID:         info.id,
+		Engagement: config.Engagement,
+		Started:    info.started,
+		Finished:   time.Now(),
+		Hashlist:   info.hashlist,
+		Mode:       info.mode,
+		Backend:    info.backend,
+		Accounts:   len(info.entries),
+		Status:     "completed",
+	}
+	if runErr != nil {
+		run.Status = "failed"
+		run.Error = runErr.Error()
+	}
+	data, err := os.ReadFile(info.hashlist)
+	if err != nil {
+		return rundb.RunRecord{}, fmt.Errorf("failed to read hashlist %s: %w", info.hashlist, err)
+	}
+	sum := sha256.Sum256(data)
+	run.HashlistSHA256 = hex.EncodeToString(sum[:])
+
+	record := rundb.RunRecord{Accounts: make([]rundb.Account, len(info.entries))}
+	accountsByHash := make(map[string][]string)
+	for i, entry := range info.entries {
+		record.Accounts[i] = rundb.Account{Name: entry.Account.Raw, Hash: entry.Hash}
+		hash := utils.NormalizeHash(entry.Hash)
+		accountsByHash[hash] = append(accountsByHash[hash], entry.Account.Raw)
+	}
+
+	steps, err := utils.ReadCrackLogSteps(info.crackLogFile)
+	if err != nil {
+		return rundb.RunRecord{}, err
+	}
+	for _, step := range steps {
+		record.Steps = append(record.Steps, rundb.Step{Name: step.Name, Finished: step.Time, Cracked: step.Cracked})
+	}
+
+	_, logEntries, err := utils.ReadCrackLog(info.crackLogFile)
+	if err != nil {
+		return rundb.RunRecord{}, err
+	}
+	for _, logEntry := range logEntries {
+		for hash, password := range utils.MatchCracked(info.entries, []string{logEntry.Line}) {
+			record.Cracks = append(record.Cracks, rundb.Crack{
+				Time:     logEntry.Time,
+				Step:     logEntry.Step,
+				Hash:     hash,
+				Password: password,
+				Accounts: accountsByHash[hash],
+			})
+		}
+	}
+
+	crackedLines, err := utils.ReadLines(info.cumulativeCrackedFile)
+	if err != nil {
+		return rundb.RunRecord{}, err
+	}
+	for hash := range utils.MatchCracked(info.entries, crackedLines) {
+		run.Cracked += len(accountsByHash[hash])
+	}
+	record.Run = run
+	return record, nil
+}
+
+// RunHistory implements the `history` subcommand for querying the run history database.
+func RunHistory(args []string) error {
+	usage := "usage: history runs | show <run-id> | step [--run <run-id>] <step> | find [--run <run-id>] <regexp>"
+	if len(args) == 0 {
+		return fmt.Errorf("%s", usage)
+	}
+	flags := flag.NewFlagSet("history "+args[0], flag.ExitOnError)
+	dbPath := flags.String("db", config.HistoryDB, "Path to the run history database")
+	runID := flags.String("run", "", "Only query this run")
+	redact := flags.String("redact", "full", "Password redaction: full, partial or hash")
+	flags.Parse(args[1:])
+
+	redaction, err := utils.ParseRedactionMode(*redact)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(*dbPath); os.IsNotExist(err) {
+		return fmt.Errorf("no run history at %s", *dbPath)
+	}
+	db, err := rundb.Open(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	switch {
+	case args[0] == "runs" && flags.NArg() == 0:
+		runs, err := db.Runs(config.Engagement)
+		if err != nil {
+			return err
+		}
+		return printRuns(os.Stdout, runs)
+	case args[0] == "show" && flags.NArg() == 1:
+		record, err := db.Record(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		return printRunRecord(os.Stdout, record)
+	case args[0] == "step" && flags.NArg() == 1:
+		cracks, err := db.CracksInStep(flags.Arg(0), *runID)
+		if err != nil {
+			return err
+		}
+		if len(cracks) == 0 {
+			names, err := db.StepNames()
+			if err != nil {
+				return err
+			}
+			color.Yellow("No accounts cracked in step %s. Recorded steps: %s", flags.Arg(0), strings.Join(names, ", "))
+			return nil
+		}
+		return printCracks(os.Stdout, cracks, redaction)
+	case args[0] == "find" && flags.NArg() == 1:
+		pattern, err := regexp.Compile(flags.Arg(0))
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		cracks, err := db.FindPasswords(pattern, *runID)
+		if err != nil {
+			return err
+		}
+		return printCracks(os.Stdout, cracks, redaction)
+	default:
+		return fmt.Errorf("%s", usage)
+	}
+}
+
+// printRuns lists runs, oldest first.
+func printRuns(w io.Writer, runs []rundb.Run) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "RUN\tENGAGEMENT\tHASHLIST\tMODE\tCRACKED\tDURATION\tSTATUS")
+	for _, run := range runs {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%d/%d\t%s\t%s\n", run.ID, valueOrNone(run.Engagement), run.Hashlist, run.Mode,
+			run.Cracked, run.Accounts, run.Finished.Sub(run.Started).Round(time.Second), run.Status)
+	}
+	return writer.Flush()
+}
+
+// printRunRecord shows a run with its steps.
+func printRunRecord(w io.Writer, record *rundb.RunRecord) error {
+	run := record.Run
+	fmt.Fprintf(w, "Run:        %s\n", run.ID)
+	fmt.Fprintf(w, "Engagement: %s\n", valueOrNone(run.Engagement))
+	fmt.Fprintf(w, "Hashlist:   %s (sha256 %s)\n", run.Hashlist, run.HashlistSHA256)
+	fmt.Fprintf(w, "Mode:       %s via %s\n", run.Mode, run.Backend)
+	fmt.Fprintf(w, "Started:    %s\n", run.Started.Format(time.RFC1123))
+	fmt.Fprintf(w, "Finished:   %s\n", run.Finished.Format(time.RFC1123))
+	fmt.Fprintf(w, "Cracked:    %d of %d accounts\n", run.Cracked, run.Accounts)
+	fmt.Fprintf(w, "Status:     %s\n", run.Status)
+	if run.Error != "" {
+		fmt.Fprintf(w, "Error:      %s\n", run.Error)
+	}
+
+	fmt.Fprintln(w)
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "STEP\tFINISHED\tNEW CRACKS")
+	for _, step := range record.Steps {
+		fmt.Fprintf(writer, "%s\t%s\t%d\n", step.Name, step.Finished.Format(time.TimeOnly), step.Cracked)
+	}
+	return writer.Flush()
+}
+
+// printCracks lists cracks with their run, step and accounts.
+func printCracks(w io.Writer, cracks []rundb.RunCrack, redaction utils.RedactionMode) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "RUN\tSTEP\tACCOUNTS\tPASSWORD")
+	for _, crack := range cracks {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", crack.RunID, crack.Step, strings.Join(crack.Accounts, ", "), redaction.Redact(crack.Password, crack.Hash))
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"hashcat-auto/cracker"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// potfileCommands maps the potfile subcommands to their implementations.
+var potfileCommands = map[string]func([]string) error{
+	"merge":    runPotfileMerge,
+	"dedupe":   runPotfileDedupe,
+	"split":    runPotfileSplit,
+	"wordlist": runPotfileWordlist,
+	"convert":  runPotfileConvert,
+}
+
+// RunPotfile implements the `potfile` command group for merging, deduplicating, splitting, exporting and
+// converting potfiles.
+func RunPotfile(args []string) error {
+	if len(args) == 0 || potfileCommands[args[0]] == nil {
+		return fmt.Errorf("usage: potfile merge|dedupe|split|wordlist|convert [flags] <potfile>...")
+	}
+	return potfileCommands[args[0]](args[1:])
+}
+
+// runPotfileMerge merges potfiles into one, dropping repeated entries.
+func runPotfileMerge(args []string) error {
+	flags := flag.NewFlagSet("potfile merge", flag.ExitOnError)
+	output := flags.String("output", "", "Path of the merged potfile (REQUIRED)")
+	flags.Parse(args)
+
+	if *output == "" || flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("--output and at least one potfile are required")
+	}
+	entries, err := readPotfiles(flags.Args())
+	if err != nil {
+		return err
+	}
+	merged := cracker.DedupePotfile(entries)
+	if err := cracker.WritePotfile(*output, merged); err != nil {
+		return err
+	}
+	color.Green("Merged %d entries from %d potfiles into %d unique entries: %s", len(entries), flags.NArg(), len(merged), *output)
+	return nil
+}
+
+// runPotfileDedupe drops repeated entries from a potfile.
+func runPotfileDedupe(args []string) error {
+	flags := flag.NewFlagSet("potfile dedupe", flag.ExitOnError)
+	output := flags.String("output", "", "Path of the deduplicated potfile (default: rewrite the potfile in place)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("exactly one potfile is required")
+	}
+	input := flags.Arg(0)
+	if *output == "" {
+		*output = input
+	}
+	entries, err := readPotfiles([]string{input})
+	if err != nil {
+		return err
+	}
+	unique := cracker.DedupePotfile(entries)
+	if err := cracker.WritePotfile(*output, unique); err != nil {
+		return err
+	}
+	color.Green("Removed %d duplicate entries, %d remain: %s", len(entries)-len(unique), len(unique), *output)
+	return nil
+}
+
+// runPotfileSplit writes one potfile per detected hash type.
+func runPotfileSplit(args []string) error {
+	flags := flag.NewFlagSet("potfile split", flag.ExitOnError)
+	outputDir := flags.String("output-dir", "", "Directory for the split potfiles (default: the potfile's directory)")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("exactly one potfile is required")
+	}
+	input := flags.Arg(0)
+	if *outputDir == "" {
+		*outputDir = filepath.Dir(input)
+	}
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", *outputDir, err)
+	}
+	entries, err := readPotfiles([]string{input})
+	if err != nil {
+		return err
+	}
+
+	// Hash types are detected by recomputing each hash, which also tells NTLM, MD5 and LM apart
+	byType := make(map[string][]cracker.PotfileEntry)
+	for _, entry := range entries {
+		result := cracker.VerifyLine(entry.Line(), "")
+		name := "unknown"
+		if result.Status == cracker.Verified {
+			name = result.Mode + "_" + strings.ToLower(result.Algorithm)
+		}
+		byType[name] = append(byType[name], entry)
+	}
+
+	names := make([]string, 0, len(byType))
+	for name := range byType {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	base := strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+	for _, name := range names {
+		output := filepath.Join(*outputDir, fmt.Sprintf("%s_%s.pot", base, name))
+		if err := cracker.WritePotfile(output, byType[name]); err != nil {
+			return err
+		}
+		color.Green("%d entries written to %s", len(byType[name]), output)
+	}
+	if len(byType["unknown"]) > 0 {
+		color.Yellow("%d entries have a hash type that could not be detected or do not match their hash.", len(byType["unknown"]))
+	}
+	return nil
+}
+
+// runPotfileWordlist exports the plaintexts of potfiles as a wordlist, most frequent first.
+func runPotfileWordlist(args []string) error {
+	flags := flag.NewFlagSet("potfile wordlist", flag.ExitOnError)
+	output := flags.String("output", "", "Path of the wordlist (REQUIRED)")
+	minCount := flags.Int("min-count", 1, "Only export plaintexts that crack at least this many hashes")
+	flags.Parse(args)
+
+	if *output == "" || flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("--output and at least one potfile are required")
+	}
+	count, err := writePotfileWordlist(flags.Args(), *output, *minCount)
+	if err != nil {
+		return err
+	}
+	color.Green("%d plaintexts written to %s", count, *output)
+	return nil
+}
+
+// writePotfileWordlist writes the plaintexts of potfiles that crack at least minCount hashes as a
+// wordlist, most frequent first, and returns how many were written.
+func writePotfileWordlist(potfiles []string, output string, minCount int) (int, error) {
+	entries, err := readPotfiles(potfiles)
+	if err != nil {
+		return 0, err
+	}
+
+	counts := make(map[string]int)
+	for _, entry := range cracker.DedupePotfile(entries) {
+		if entry.Plain != "" {
+			counts[entry.Plain]++
+		}
+	}
+	plains := make([]string, 0, len(counts))
+	for plain, count := range counts {
+		if count >= minCount {
+			plains = append(plains, plain)
+		}
+	}
+	sort.Slice(plains, func(i, j int) bool {
+		if counts[plains[i]] != counts[plains[j]] {
+			return counts[plains[i]] > counts[plains[j]]
+		}
+		return plains[i] < plains[j]
+	})
+
+	// Plaintexts with control bytes or invalid UTF-8 are written as $HEX[...], which hashcat decodes
+	var b strings.Builder
+	for _, plain := range plains {
+		b.WriteString(cracker.EncodePlain(plain) + "\n")
+	}
+	if err := os.WriteFile(output, []byte(b.String()), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write wordlist %s: %w", output, err)
+	}
+	return len(plains), nil
+}
+
+// runPotfileConvert converts between hashcat potfiles and John the Ripper pot files.
+func runPotfileConvert(args []string) error {
+	flags := flag.NewFlagSet("potfile convert", flag.ExitOnError)
+	to := flags.String("to", "", "Target format: john or hashcat (REQUIRED)")
+	output := flags.String("output", "", "Path of the converted file (REQUIRED)")
+	flags.Parse(args)
+
+	if *output == "" || flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("--output and exactly one input file are required")
+	}
+
+	var converted []cracker.PotfileEntry
+	var unchanged int
+	switch *to {
+	case "john":
+		entries, err := readPotfiles(flags.Args())
+		if err != nil {
+			return err
+		}
+		converted, unchanged = cracker.ToJohn(entries)
+	case "hashcat":
+		var err error
+		converted, unchanged, err = cracker.ReadJohnPot(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+	default:
+		flags.Usage()
+		return fmt.Errorf("--to must be john or hashcat")
+	}
+
+	converted = cracker.DedupePotfile(converted)
+	if err := cracker.WritePotfile(*output, converted); err != nil {
+		return err
+	}
+	color.Green("%d entries written to %s", len(converted), *output)
+	if unchanged > 0 {
+		color.Yellow("%d entries could not be converted and were copied unchanged.", unchanged)
+	}
+	return nil
+}
+
+// readPotfiles reads the entries of several potfiles in order, warning about lines that could not be parsed.
+func readPotfiles(filenames []string) ([]cracker.PotfileEntry, error) {
+	var entries []cracker.PotfileEntry
+	for _, filename := range filenames {
+		fileEntries, invalid, err := cracker.ReadPotfileEntries(filename)
+		if err != nil {
+			return nil, err
+		}
+		if invalid > 0 {
+			color.Yellow("Skipped %d lines of %s that are not hash:plain entries.", invalid, filename)
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return entries, nil
+}
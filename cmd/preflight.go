@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"hashcat-auto/utils"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// maxPreflightListed caps how many accounts or groups each pre-flight finding lists in the terminal.
+const maxPreflightListed = 10
+
+// runPreflight reports blank, LM and duplicate hashes found in the hashlist without running hashcat.
+// It returns the accounts whose blank password is known for the hash mode, to be marked as cracked.
+func runPreflight(entries []utils.HashlistEntry, hashcatMode string) []utils.HashlistEntry {
+	color.Yellow("Running pre-flight hash analysis...")
+	findings := utils.AnalyzePreflight(entries)
+
+	var blank []utils.HashlistEntry
+	switch hashcatMode {
+	case "1000":
+		blank = findings.BlankNT
+	case "3000":
+		blank = findings.BlankLM
+	}
+	if len(blank) > 0 {
+		color.Red("%d accounts have a blank password:", len(blank))
+		printPreflightAccounts(blank)
+	}
+
+	if len(findings.StoredLM) > 0 {
+		color.Red("%d accounts have an LM hash stored (%d with a password of at most 7 characters), crack them with -m 3000:",
+			len(findings.StoredLM), findings.ShortLM)
+		printPreflightAccounts(findings.StoredLM)
+	}
+
+	if len(findings.Duplicates) > 0 {
+		color.Red("%d accounts share a hash with another account, in %d groups:", findings.DuplicateAccounts(), len(findings.Duplicates))
+		for i, group := range findings.Duplicates {
+			if i >= maxPreflightListed {
+				color.Red("  ... and %d more groups", len(findings.Duplicates)-maxPreflightListed)
+				break
+			}
+			accounts := make([]string, len(group))
+			for j, entry := range group {
+				accounts[j] = entry.Account.Raw
+			}
+			color.Red("  %s (%d accounts): %s", group[0].Hash, len(group), strings.Join(accounts, ", "))
+		}
+	}
+
+	if len(blank) == 0 && len(findings.StoredLM) == 0 && len(findings.Duplicates) == 0 {
+		color.Green("No blank, LM or duplicate hashes found.")
+	}
+	return blank
+}
+
+// printPreflightAccounts lists the accounts of a pre-flight finding.
+func printPreflightAccounts(entries []utils.HashlistEntry) {
+	for i, entry := range entries {
+		if i >= maxPreflightListed {
+			color.Red("  ... and %d more", len(entries)-maxPreflightListed)
+			return
+		}
+		color.Red("  %s", entry.Account.Raw)
+	}
+}
+
+// appendPreflightCracked adds the blank-password hashes found before cracking to the cracked file,
+// in potfile format, unless hashcat already reported them. It returns the lines added.
+func appendPreflightCracked(crackedFile string, blank []utils.HashlistEntry) ([]string, error) {
+	if len(blank) == 0 {
+		return nil, nil
+	}
+	lines, err := utils.ReadLines(crackedFile)
+	if err != nil {
+		return nil, err
+	}
+	cracked := utils.MatchCracked(blank, lines)
+
+	var missing []string
+	for _, entry := range blank {
+		if _, ok := cracked[entry.Hash]; !ok {
+			missing = append(missing, entry.Hash+":")
+			cracked[entry.Hash] = ""
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+	if err := utils.AppendToFile(crackedFile, missing); err != nil {
+		return nil, fmt.Errorf("error writing blank passwords to file: %w", err)
+	}
+	return missing, nil
+}
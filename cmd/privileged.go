@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"hashcat-auto/config"
+	"hashcat-auto/report"
+	"hashcat-auto/utils"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// sensitiveGroups returns the built-in sensitive groups plus those from config.json and the comma-separated extra list.
+func sensitiveGroups(extra string) []string {
+	groups := append([]string(nil), utils.DefaultSensitiveGroups...)
+	for _, group := range append(append([]string(nil), config.SensitiveGroups...), SplitList(extra)...) {
+		duplicate := false
+		for _, existing := range groups {
+			if strings.EqualFold(existing, group) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// analyzePrivileged loads the group membership exports and finds cracked accounts in sensitive groups.
+func analyzePrivileged(entries []utils.HashlistEntry, crackedByHash map[string]string, groupFiles, sensitive []string) (*report.PrivilegedStats, error) {
+	memberships, err := utils.LoadGroupMemberships(groupFiles)
+	if err != nil {
+		return nil, fmt.Errorf("error loading group memberships: %w", err)
+	}
+	stats := report.AnalyzePrivileged(entries, crackedByHash, memberships, sensitive)
+	return &stats, nil
+}
+
+// printPrivilegedSummary lists cracked accounts in sensitive groups in the terminal, masking passwords per mode.
+func printPrivilegedSummary(stats *report.PrivilegedStats, mode utils.RedactionMode) {
+	stats = stats.Redacted(mode)
+	if len(stats.Cracked) == 0 {
+		color.Green("No accounts in sensitive groups were cracked (%d privileged accounts in hashlist).", stats.Privileged)
+		return
+	}
+	color.Red("Cracked %d of %d accounts in sensitive groups:", len(stats.Cracked), stats.Privileged)
+	for _, account := range stats.Cracked {
+		color.Red("  %s [%s]: %s", account.Account, strings.Join(account.Groups, ", "), account.Password)
+	}
+}
@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"hashcat-auto/config"
+	"hashcat-auto/report"
+	"hashcat-auto/utils"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// reportWriters maps report formats to their file extension and renderer.
+var reportWriters = map[string]struct {
+	extension string
+	write     func(io.Writer, *report.Report) error
+}{
+	"md":   {".md", report.WriteMarkdown},
+	"html": {".html", report.WriteHTML},
+	"json": {".json", report.WriteJSON},
+}
+
+// RunReport implements the `report` subcommand, analysing cracked plaintexts into Markdown, HTML and JSON reports.
+func RunReport(args []string) error {
+	flags := flag.NewFlagSet("report", flag.ExitOnError)
+	cracked := flags.String("cracked", "", "Path to the cracked file, e.g. cache/cumulative_cracked_<timestamp>.txt (REQUIRED)")
+	output := flags.String("output", "", "Output path without extension (default: report_<timestamp> in the cache directory)")
+	formats := flags.String("formats", "md,html,json", "Comma-separated report formats (md, html, json)")
+	top := flags.Int("top", 10, "Number of entries to show in each top-N table")
+	title := flags.String("title", "Cracked Password Analysis", "Report title")
+	hashlist := flags.String("hashlist", "", "Path to the hashlist, enables password reuse analysis across accounts")
+	adminConventions := flags.String("admin-conventions", strings.Join(defaultAdminConventions(), ","), "Comma-separated admin account naming conventions, {user} is the standard username")
+	maxEditDistance := flags.Int("max-edit-distance", 3, "Flag admin/standard pairs whose passwords are within this many edits")
+	groups := flags.String("groups", "", "Comma-separated group membership exports (ldapdomaindump, BloodHound JSON or group,member CSV), needs --hashlist")
+	extraGroups := flags.String("sensitive-groups", "", "Comma-separated extra sensitive groups besides Domain Admins and Enterprise Admins")
+	adFiles := flags.String("ad", "", "Comma-separated ldapdomaindump domain_users.json or ldapsearch LDIF files with account attributes, needs --hashlist")
+	enabledOnly := flags.Bool("enabled-only", false, "Only analyse accounts that --ad marks as enabled")
+	staleDays := flags.Int("stale-days", 365, "Passwords last set more than this many days ago count as stale")
+	breachFile := flags.String("breach", "", "Sorted hash:count breach file, e.g. the Pwned Passwords NTLM or SHA1 \"ordered by hash\" download, needs --hashlist")
+	crackLog := flags.String("crack-log", "", "Path to the run's crack_log_<timestamp>.txt for time-to-crack analysis")
+	policyMinLength := flags.Int("policy-min-length", 0, "Password policy minimum length (enables the policy section)")
+	policyClasses := flags.Int("policy-classes", 0, "Password policy minimum number of character classes (lower, upper, digit, special)")
+	policyBanned := flags.String("policy-banned", "", "Comma-separated words banned by the password policy")
+	verify := flags.Bool("verify", true, "Recompute supported hashes first and leave out cracked entries whose password does not match")
+	hashMode := flags.String("mode", "", "Hashcat mode for --verify (default: try every supported type matching each hash's format)")
+	redact := flags.String("redact", "full", "Password redaction: full, partial or hash for every output, or per output such as md=partial,html=partial,json=full,terminal=hash")
+	flags.Parse(args)
+
+	if *cracked == "" {
+		flags.Usage()
+		return fmt.Errorf("--cracked is required")
+	}
+	if *enabledOnly && (*adFiles == "" || *hashlist == "") {
+		return fmt.Errorf("--enabled-only needs --ad and --hashlist")
+	}
+	redaction, err := utils.ParseRedactionModes(*redact)
+	if err != nil {
+		return err
+	}
+
+	passwords, err := utils.ReadCrackedPasswords(*cracked)
+	if err != nil {
+		return fmt.Errorf("error reading cracked passwords: %w", err)
+	}
+	var mismatched map[string]bool
+	if *verify {
+		mismatched, err = verifyCracked(*cracked, *hashMode)
+		if err != nil {
+			return err
+		}
+	}
+	crackedLines, err := utils.ReadLines(*cracked)
+	if err != nil {
+		return fmt.Errorf("error reading cracked passwords: %w", err)
+	}
+	if len(mismatched) > 0 {
+		color.Red("Leaving the mismatching entries out of the report.")
+		crackedLines = dropLines(crackedLines, mismatched)
+		passwords = utils.CrackedPasswords(crackedLines)
+	}
+
+	r := &report.Report{
+		Title:     *title,
+		Source:    *cracked,
+		Generated: time.Now(),
+		Passwords: report.AnalyzePasswords(passwords, *top),
+	}
+	r.Passwords.Blank = utils.CountBlankPasswords(crackedLines)
+	r.Passwords.Basis = "Passwords are counted per cracked line, so machine accounts, history entries and accounts sharing a hash are all included."
+	var enabled []utils.HashlistEntry
+
+	if *hashlist != "" {
+		entries, err := utils.ParseHashlist(*hashlist)
+		if err != nil {
+			return fmt.Errorf("error reading hashlist: %w", err)
+		}
+		crackedByHash := utils.MatchCracked(entries, crackedLines)
+
+		if *adFiles != "" {
+			attributes, err := utils.LoadADAttributes(SplitList(*adFiles))
+			if err != nil {
+				return fmt.Errorf("error loading AD attributes: %w", err)
+			}
+			utils.AttachADAttributes(entries, attributes)
+			if *enabledOnly {
+				entries = utils.FilterEnabled(entries)
+				enabled = entries
+				crackedLines = utils.FilterCracked(entries, crackedLines)
+				passwords = utils.CrackedPasswords(crackedLines)
+				r.Passwords = report.AnalyzePasswords(passwords, *top)
+				r.Passwords.Blank = utils.CountBlankPasswords(crackedLines)
+				r.Passwords.Basis = "Passwords are counted per cracked line, limited to lines whose hash belongs to an enabled account."
+				color.Yellow("Analysing %d enabled accounts only.", len(entries))
+			}
+			accounts := report.AnalyzeCrackedAccounts(entries, crackedByHash, r.Generated, *staleDays)
+			r.Accounts = &accounts
+		}
+
+		reuse := report.AnalyzeReuse(entries, crackedByHash)
+		r.Reuse = &reuse
+
+		if *breachFile != "" {
+			color.Yellow("Looking up hashes in %s...", *breachFile)
+			breaches, err := utils.LookupBreaches(entries, *breachFile)
+			if err != nil {
+				return fmt.Errorf("error looking up breached passwords: %w", err)
+			}
+			breachStats := report.AnalyzeBreaches(entries, breaches, crackedByHash, filepath.Base(*breachFile))
+			r.Breaches = &breachStats
+		}
+
+		if histories := utils.LinkPasswordHistory(entries); len(histories) > 0 {
+			history := report.AnalyzeHistory(histories, crackedByHash)
+			r.History = &history
+		}
+
+		accountPairs, err := utils.FindAdminPairs(entries, SplitList(*adminConventions))
+		if err != nil {
+			return err
+		}
+		pairs := report.AnalyzePairs(accountPairs, crackedByHash, *maxEditDistance)
+		r.Pairs = &pairs
+		if pairs.Flagged > 0 {
+			color.Red("%d admin/standard account pairs share identical or related passwords.", pairs.Flagged)
+		}
+
+		if *groups != "" {
+			r.Privileged, err = analyzePrivileged(entries, crackedByHash, SplitList(*groups), sensitiveGroups(*extraGroups))
+			if err != nil {
+				return err
+			}
+			printPrivilegedSummary(r.Privileged, utils.RedactionFor(redaction, "terminal"))
+		}
+	}
+
+	// The policy section comes last so that it covers the same accounts as the rest of the report
+	if *policyMinLength > 0 || *policyClasses > 0 || *policyBanned != "" {
+		policy := report.Policy{MinLength: *policyMinLength, MinClasses: *policyClasses, BannedWords: SplitList(*policyBanned)}
+		var crackTimes []report.CrackTime
+		if *crackLog != "" {
+			crackTimes, err = readCrackTimes(*crackLog, mismatched, enabled, *enabledOnly)
+			if err != nil {
+				return err
+			}
+		}
+		policyStats := report.AnalyzePolicy(policy, passwords, crackTimes)
+		r.Policy = &policyStats
+	}
+
+	outputBase := *output
+	if outputBase == "" {
+		outputBase = filepath.Join(config.CacheDir, fmt.Sprintf("report_%s", r.Generated.Format("20060102_150405")))
+	}
+	for _, format := range SplitList(*formats) {
+		if err := writeReport(r.Redacted(utils.RedactionFor(redaction, format)), format, outputBase); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defaultAdminConventions returns the configured admin naming conventions, or the built-in ones.
+func defaultAdminConventions() []string {
+	if len(config.AdminConventions) > 0 {
+		return config.AdminConventions
+	}
+	return utils.DefaultAdminConventions
+}
+
+// readCrackTimes reads a crack log into how long after the run's start each password was cracked.
+// Blank passwords, mismatched lines and, with enabledOnly, lines of other accounts are skipped so the
+// time-to-crack buckets count the same passwords as the policy checks.
+func readCrackTimes(crackLog string, mismatched map[string]bool, enabled []utils.HashlistEntry, enabledOnly bool) ([]report.CrackTime, error) {
+	start, entries, err := utils.ReadCrackLog(crackLog)
+	if err != nil {
+		return nil, fmt.Errorf("error reading crack log: %w", err)
+	}
+	var inScope map[string]bool
+	if enabledOnly {
+		lines := make([]string, len(entries))
+		for i, entry := range entries {
+			lines[i] = entry.Line
+		}
+		inScope = make(map[string]bool)
+		for _, line := range utils.FilterCracked(enabled, lines) {
+			inScope[line] = true
+		}
+	}
+
+	var crackTimes []report.CrackTime
+	for _, entry := range entries {
+		if entry.Password == "" || mismatched[strings.TrimRight(entry.Line, "\r")] || (enabledOnly && !inScope[entry.Line]) {
+			continue
+		}
+		crackTimes = append(crackTimes, report.CrackTime{Password: entry.Password, Elapsed: entry.Time.Sub(start)})
+	}
+	return crackTimes, nil
+}
+
+// writeReport renders the report in one format to outputBase plus the format's extension.
+func writeReport(r *report.Report, format, outputBase string) error {
+	writer, ok := reportWriters[format]
+	if !ok {
+		return fmt.Errorf("unsupported report format %q", format)
+	}
+
+	filename := outputBase + writer.extension
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return fmt.Errorf("failed to create report directory for %s: %w", filename, err)
+	}
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create report file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	if err := writer.write(file, r); err != nil {
+		return fmt.Errorf("failed to write report file %s: %w", filename, err)
+	}
+	color.Green("Report written: %s", filename)
+	return nil
+}
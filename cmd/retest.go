@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"hashcat-auto/config"
+	"hashcat-auto/report"
+	"hashcat-auto/utils"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+)
+
+// RunRetest implements the `retest` subcommand: `baseline` records the accounts an assessment
+// compromised as keyed fingerprints, and `check` tells which of them still have the same hash in a new
+// dump, without cracking anything.
+func RunRetest(args []string) error {
+	usage := "usage: retest baseline --hashlist <file> --cracked <file> [--output <baseline>] | retest check [--output <file>] <baseline> <new hashlist>"
+	if len(args) == 0 || (args[0] != "baseline" && args[0] != "check") {
+		return fmt.Errorf("%s", usage)
+	}
+	flags := flag.NewFlagSet("retest "+args[0], flag.ExitOnError)
+	keyFile := flags.String("key", filepath.Join(config.CacheDir, "retest.key"), "Key file for the account fingerprints, created by `baseline` if missing; keep it out of the baseline's directory")
+	hashlist := flags.String("hashlist", "", "Hashlist of the assessment (baseline)")
+	cracked := flags.String("cracked", "", "Cracked file or potfile of the assessment (baseline)")
+	output := flags.String("output", "", "Baseline file to write (baseline), or JSON results file (check)")
+	flags.Parse(args[1:])
+
+	if args[0] == "baseline" {
+		if *hashlist == "" || *cracked == "" || flags.NArg() != 0 {
+			flags.Usage()
+			return fmt.Errorf("%s", usage)
+		}
+		if *output == "" {
+			*output = "retest_baseline.json"
+		}
+		return createBaseline(*hashlist, *cracked, *keyFile, *output)
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return fmt.Errorf("%s", usage)
+	}
+	return checkBaseline(flags.Arg(0), flags.Arg(1), *keyFile, *output)
+}
+
+// createBaseline writes the baseline of the accounts cracked in an assessment.
+func createBaseline(hashlist, crackedFile, keyFile, output string) error {
+	entries, err := utils.ParseHashlist(hashlist)
+	if err != nil {
+		return fmt.Errorf("error reading hashlist: %w", err)
+	}
+	crackedLines, err := utils.ReadLines(crackedFile)
+	if err != nil {
+		return fmt.Errorf("error reading cracked passwords: %w", err)
+	}
+	if _, err := os.Stat(keyFile); os.IsNotExist(err) && sameDir(keyFile, output) {
+		return fmt.Errorf("refusing to create key %s next to the baseline %s: pass a --key outside that directory", keyFile, output)
+	}
+	key, created, err := utils.LoadOrCreateBaselineKey(keyFile)
+	if err != nil {
+		return err
+	}
+	if created {
+		color.Yellow("Created key %s. Keep it apart from the baseline: it is needed for the re-test.", keyFile)
+	}
+
+	baseline := utils.NewBaseline(key, entries, utils.MatchCracked(entries, crackedLines))
+	baseline.Engagement = config.Engagement
+	baseline.Hashlist = hashlist
+	if err := utils.SaveBaseline(output, baseline); err != nil {
+		return err
+	}
+	color.Green("Baseline of %d compromised accounts written: %s", len(baseline.Accounts), output)
+	return nil
+}
+
+// sameDir reports whether two files are in the same directory.
+func sameDir(a, b string) bool {
+	dirA, errA := filepath.Abs(filepath.Dir(a))
+	dirB, errB := filepath.Abs(filepath.Dir(b))
+	return errA == nil && errB == nil && dirA == dirB
+}
+
+// checkBaseline reports which baseline accounts still have the same hash in a new hashlist.
+func checkBaseline(baselineFile, hashlist, keyFile, output string) error {
+	baseline, err := utils.LoadBaseline(baselineFile)
+	if err != nil {
+		return err
+	}
+	key, err := utils.ReadBaselineKey(keyFile)
+	if err != nil {
+		return err
+	}
+	if err := baseline.CheckKey(key); err != nil {
+		return fmt.Errorf("%s: %w", keyFile, err)
+	}
+	entries, err := utils.ParseHashlist(hashlist)
+	if err != nil {
+		return fmt.Errorf("error reading hashlist: %w", err)
+	}
+
+	stats := report.CheckBaseline(baseline, key, entries)
+	color.Green("Baseline from %s: %d compromised accounts.", baseline.Created.Format("2006-01-02"), stats.Compromised)
+	color.Green("Password changed: %d (%.1f%% of the accounts still present)", len(stats.Changed), stats.Remediated)
+	if len(stats.Unchanged) > 0 {
+		color.Red("Password NOT changed since compromise: %d", len(stats.Unchanged))
+		for _, account := range stats.Unchanged {
+			fmt.Printf("  %s\n", account)
+		}
+	}
+	if len(stats.Missing) > 0 {
+		color.Yellow("Not in the new hashlist (removed or renamed): %d", len(stats.Missing))
+		for _, account := range stats.Missing {
+			fmt.Printf("  %s\n", account)
+		}
+	}
+
+	if output != "" {
+		data, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode results: %w", err)
+		}
+		if err := os.WriteFile(output, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", output, err)
+		}
+		color.Green("Results written: %s", output)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"hashcat-auto/cracker"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// maxMismatchesListed caps how many mismatching entries are printed.
+const maxMismatchesListed = 20
+
+// RunVerify implements the `verify` subcommand, recomputing the hash of every cracked entry from its
+// plaintext and reporting entries whose password does not produce the hash.
+func RunVerify(args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ExitOnError)
+	cracked := flags.String("cracked", "", "Path to the cracked file or potfile (REQUIRED)")
+	mode := flags.String("mode", "", "Hashcat mode of the entries (default: try every supported type matching each hash's format)")
+	clean := flags.String("clean", "", "Write the entries that did not fail verification to this file")
+	flags.Parse(args)
+
+	if *cracked == "" {
+		flags.Usage()
+		return fmt.Errorf("--cracked is required")
+	}
+
+	mismatched, err := verifyCracked(*cracked, *mode)
+	if err != nil {
+		return err
+	}
+
+	if *clean != "" {
+		if err := writeVerifiedLines(*cracked, *clean, mismatched); err != nil {
+			return err
+		}
+		color.Green("Entries that did not fail verification written to %s", *clean)
+	}
+	if len(mismatched) > 0 {
+		return fmt.Errorf("%d cracked entries do not match their hash", len(mismatched))
+	}
+	return nil
+}
+
+// verifyCracked verifies a cracked file, prints a summary and the mismatching entries, and returns the
+// mismatching lines.
+func verifyCracked(filename, mode string) (map[string]bool, error) {
+	if mode != "" && !cracker.VerifySupported(mode) {
+		return nil, fmt.Errorf("mode %s cannot be verified (supported: %s)", mode, strings.Join(cracker.VerifyModes(), ", "))
+	}
+	color.Yellow("Verifying cracked entries in %s...", filename)
+	results, err := cracker.VerifyFile(filename, mode)
+	if err != nil {
+		return nil, fmt.Errorf("error verifying cracked entries: %w", err)
+	}
+
+	if mode == "" {
+		// Without a mode a 32-character hash verifies as NTLM, MD5 or LM, so entries of another type
+		// than the rest of the file are flagged instead
+		if common := cracker.MarkOtherTypes(results); common != "" {
+			color.Yellow("Detected hash type: %s (mode %s). Pass --mode to check against a known type.", cracker.ModeName(common), common)
+		}
+	}
+
+	var verified, unsupported int
+	var mismatches []cracker.Verification
+	for _, result := range results {
+		switch result.Status {
+		case cracker.Verified:
+			verified++
+		case cracker.Unsupported:
+			unsupported++
+		case cracker.Mismatch, cracker.OtherType:
+			mismatches = append(mismatches, result)
+		}
+	}
+
+	color.Green("%d of %d cracked entries verified.", verified, len(results))
+	if unsupported > 0 {
+		color.Yellow("%d entries have a hash type that cannot be verified.", unsupported)
+	}
+	if len(mismatches) == 0 {
+		return nil, nil
+	}
+
+	color.Red("%d entries do not match their hash or the file's hash type:", len(mismatches))
+	for i, mismatch := range mismatches {
+		if i == maxMismatchesListed {
+			color.Red("  ... and %d more", len(mismatches)-maxMismatchesListed)
+			break
+		}
+		if mismatch.Status == cracker.OtherType {
+			color.Red("  line %d: %s (verifies as %s, unlike the rest of the file)", mismatch.Line, mismatch.Hash, mismatch.Algorithm)
+		} else {
+			color.Red("  line %d: %s (%s)", mismatch.Line, mismatch.Hash, mismatch.Algorithm)
+		}
+	}
+
+	lines, err := readRawLines(filename)
+	if err != nil {
+		return nil, err
+	}
+	mismatched := make(map[string]bool, len(mismatches))
+	for _, mismatch := range mismatches {
+		mismatched[lines[mismatch.Line-1]] = true
+	}
+	return mismatched, nil
+}
+
+// dropLines returns the lines that are not in the excluded set.
+func dropLines(lines []string, excluded map[string]bool) []string {
+	if len(excluded) == 0 {
+		return lines
+	}
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if !excluded[strings.TrimRight(line, "\r")] {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+// writeVerifiedLines copies a cracked file, leaving out the mismatching lines.
+func writeVerifiedLines(filename, output string, mismatched map[string]bool) error {
+	lines, err := readRawLines(filename)
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	for _, line := range dropLines(lines, mismatched) {
+		if line != "" {
+			b.WriteString(line + "\n")
+		}
+	}
+	if err := os.WriteFile(output, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	return nil
+}
+
+// readRawLines reads every line of a file, blank ones included, with carriage returns removed, so that
+// line numbers match those reported by cracker.VerifyFile.
+func readRawLines(filename string) ([]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, "\r")
+	}
+	return lines, nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"hashcat-auto/config"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+)
+
+// RunWorkspace implements the `workspace` subcommand for creating and listing engagement workspaces.
+func RunWorkspace(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: workspace create <name> | list | show <name>")
+	}
+	flags := flag.NewFlagSet("workspace "+args[0], flag.ExitOnError)
+	flags.Parse(args[1:])
+
+	switch args[0] {
+	case "create":
+		if flags.NArg() != 1 {
+			return fmt.Errorf("usage: workspace create <name>")
+		}
+		dir, err := config.CreateEngagement(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		color.Green("Created engagement workspace %s", dir)
+		fmt.Printf("Config overrides: %s\n", filepath.Join(dir, "config.json"))
+		fmt.Printf("Use it with --engagement=%s on any command.\n", flags.Arg(0))
+		return nil
+	case "list":
+		names, err := config.ListEngagements()
+		if err != nil {
+			return err
+		}
+		if len(names) == 0 {
+			color.Yellow("No engagement workspaces in %s", config.WorkspacesDir)
+			return nil
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+	case "show":
+		if flags.NArg() != 1 {
+			return fmt.Errorf("usage: workspace show <name>")
+		}
+		return showWorkspace(flags.Arg(0))
+	default:
+		return fmt.Errorf("unknown workspace command %q (expected create, list or show)", args[0])
+	}
+}
+
+// showWorkspace prints the paths and settings an engagement runs with.
+func showWorkspace(name string) error {
+	if err := config.UseEngagement(name); err != nil {
+		return err
+	}
+	runs, err := filepath.Glob(filepath.Join(config.CacheDir, "crack_log_*.txt"))
+	if err != nil {
+		return fmt.Errorf("failed to list runs: %w", err)
+	}
+	potfileEntries, err := countPotfileEntries(config.EngagementPotfile)
+	if err != nil {
+		return err
+	}
+
+	color.Green("Engagement %s", config.Engagement)
+	fmt.Printf("Workspace:      %s\n", config.EngagementDir)
+	fmt.Printf("Cache:          %s\n", config.CacheDir)
+	fmt.Printf("Potfile:        %s (%d entries)\n", config.EngagementPotfile, potfileEntries)
+	fmt.Printf("Runs:           %d\n", len(runs))
+	fmt.Printf("Hashcat:        %s\n", config.DefaultHashcatPath)
+	fmt.Printf("Wordlist:       %s\n", config.DefaultWordlist)
+	fmt.Printf("Custom potfile: %s\n", valueOrNone(config.DefaultPotfile))
+	fmt.Printf("Shared potfile: %s (wordlist only, with --shared-potfile-wordlist)\n", valueOrNone(config.SharedPotfile))
+	return nil
+}
+
+// countPotfileEntries counts the entries of a potfile, treating a missing potfile as empty.
+func countPotfileEntries(filename string) (int, error) {
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return 0, nil
+	}
+	entries, err := readPotfiles([]string{filename})
+	return len(entries), err
+}
+
+// valueOrNone returns a setting for display, or "none" when it is empty.
+func valueOrNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
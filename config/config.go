@@ -18,20 +18,30 @@ var (
 	DefaultPassphraseRule2 string
 	DefaultDictionary      string
 	CacheDir               string
+	WorkspacesDir          string
+	CorpusFile             string
+	HistoryDB              string
+	AdminConventions       []string
+	SensitiveGroups        []string
 )
 
 // Config struct to map JSON keys
 type Config struct {
-	HashcatPath     string `json:"hashcat_path"`
-	Wordlist        string `json:"wordlist"`
-	Potfile         string `json:"potfile"`
-	ClemRule        string `json:"clem_rule"`
-	RulesFull       string `json:"rules_full"`
-	Passphrases     string `json:"passphrases"`
-	PassphraseRule1 string `json:"passphrase_rule1"`
-	PassphraseRule2 string `json:"passphrase_rule2"`
-	Dictionary      string `json:"dictionary"`
-	CacheDir        string `json:"cache_dir"`
+	HashcatPath      string   `json:"hashcat_path"`
+	Wordlist         string   `json:"wordlist"`
+	Potfile          string   `json:"potfile"`
+	ClemRule         string   `json:"clem_rule"`
+	RulesFull        string   `json:"rules_full"`
+	Passphrases      string   `json:"passphrases"`
+	PassphraseRule1  string   `json:"passphrase_rule1"`
+	PassphraseRule2  string   `json:"passphrase_rule2"`
+	Dictionary       string   `json:"dictionary"`
+	CacheDir         string   `json:"cache_dir"`
+	WorkspacesDir    string   `json:"workspaces_dir"`
+	Corpus           string   `json:"corpus"`
+	HistoryDB        string   `json:"history_db"`
+	AdminConventions []string `json:"admin_conventions"`
+	SensitiveGroups  []string `json:"sensitive_groups"`
 }
 
 // LoadConfig reads the config.json file and assigns values to global variables
@@ -59,6 +69,20 @@ func LoadConfig(configPath string) error {
 	DefaultPassphraseRule2 = cfg.PassphraseRule2
 	DefaultDictionary = cfg.Dictionary
 	CacheDir = cfg.CacheDir
+	WorkspacesDir = cfg.WorkspacesDir
+	if WorkspacesDir == "" {
+		WorkspacesDir = "workspaces/"
+	}
+	CorpusFile = cfg.Corpus
+	if CorpusFile == "" {
+		CorpusFile = "found_passwords.tsv"
+	}
+	HistoryDB = cfg.HistoryDB
+	if HistoryDB == "" {
+		HistoryDB = "runs.db"
+	}
+	AdminConventions = cfg.AdminConventions
+	SensitiveGroups = cfg.SensitiveGroups
 
 	// Ensure cache directory exists
 	if err := os.MkdirAll(CacheDir, 0755); err != nil {
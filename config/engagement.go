@@ -0,0 +1,142 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+// Engagement settings, set by UseEngagement
+var (
+	Engagement        string // Active engagement, empty when no workspace is used
+	EngagementDir     string
+	EngagementPotfile string // Potfile hashcat uses instead of its default one
+	SharedPotfile     string // The main config's potfile, which an engagement may only use as a wordlist
+)
+
+// engagementConfig is the file in a workspace holding config overrides.
+const engagementConfig = "config.json"
+
+var engagementName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// EngagementPath returns the workspace directory of an engagement.
+func EngagementPath(name string) (string, error) {
+	if !engagementName.MatchString(name) {
+		return "", fmt.Errorf("invalid engagement name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return filepath.Join(WorkspacesDir, name), nil
+}
+
+// CreateEngagement creates an engagement workspace with its cache directory and an empty overrides file.
+func CreateEngagement(name string) (string, error) {
+	dir, err := EngagementPath(name)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(dir); err == nil {
+		return "", fmt.Errorf("engagement %q already exists: %s", name, dir)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "cache"), 0700); err != nil {
+		return "", fmt.Errorf("failed to create workspace %s: %v", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, engagementConfig), []byte("{}\n"), 0600); err != nil {
+		return "", fmt.Errorf("failed to create workspace config: %v", err)
+	}
+	return dir, nil
+}
+
+// ListEngagements returns the names of the existing engagement workspaces.
+func ListEngagements() ([]string, error) {
+	entries, err := os.ReadDir(WorkspacesDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspaces directory: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && engagementName.MatchString(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// UseEngagement switches to an engagement workspace: its own cache directory, potfile and run history,
+// with the workspace config overriding the main one. The main config's potfile is kept aside as
+// SharedPotfile and no longer used by default.
+func UseEngagement(name string) error {
+	dir, err := EngagementPath(name)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("engagement %q does not exist, create it with `workspace create %s`", name, name)
+	}
+
+	SharedPotfile = DefaultPotfile
+	DefaultPotfile = ""
+	if err := applyOverrides(filepath.Join(dir, engagementConfig)); err != nil {
+		return err
+	}
+
+	Engagement = name
+	EngagementDir = dir
+	EngagementPotfile = filepath.Join(dir, "hashcat.potfile")
+	HistoryDB = filepath.Join(dir, "runs.db")
+	CacheDir = filepath.Join(dir, "cache") + string(filepath.Separator)
+	if err := os.MkdirAll(CacheDir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	return nil
+}
+
+// applyOverrides applies the non-empty settings of a workspace config. The cache and workspaces
+// directories and the run history database cannot be overridden.
+func applyOverrides(configPath string) error {
+	file, err := os.Open(configPath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open workspace config: %v", err)
+	}
+	defer file.Close()
+
+	var cfg Config
+	if err := json.NewDecoder(file).Decode(&cfg); err != nil {
+		return fmt.Errorf("failed to decode workspace config %s: %v", configPath, err)
+	}
+
+	for _, setting := range []struct {
+		value  string
+		target *string
+	}{
+		{cfg.HashcatPath, &DefaultHashcatPath},
+		{cfg.Wordlist, &DefaultWordlist},
+		{cfg.Potfile, &DefaultPotfile},
+		{cfg.ClemRule, &DefaultClemRule},
+		{cfg.RulesFull, &DefaultRulesFull},
+		{cfg.Passphrases, &DefaultPassphrases},
+		{cfg.PassphraseRule1, &DefaultPassphraseRule1},
+		{cfg.PassphraseRule2, &DefaultPassphraseRule2},
+		{cfg.Dictionary, &DefaultDictionary},
+		{cfg.Corpus, &CorpusFile},
+	} {
+		if setting.value != "" {
+			*setting.target = setting.value
+		}
+	}
+	if cfg.AdminConventions != nil {
+		AdminConventions = cfg.AdminConventions
+	}
+	if cfg.SensitiveGroups != nil {
+		SensitiveGroups = cfg.SensitiveGroups
+	}
+	return nil
+}
@@ -0,0 +1,408 @@
+package cracker
+
+import (
+	"bufio"
+	"fmt"
+	"hashcat-auto/utils"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Options configures a Go backend run.
+type Options struct {
+	Potfile string    // Potfile used unless the command passes --potfile-path
+	Stdout  io.Writer // Where cracked hashes, --show output and status lines are written
+	Workers int       // Number of cracking goroutines, 0 for one per CPU
+}
+
+// command is the subset of a hashcat command line understood by the Go backend.
+type command struct {
+	attack      string
+	mode        string
+	rules       []string
+	show        bool
+	potfile     string
+	status      bool
+	statusTimer time.Duration
+	positional  []string
+}
+
+// parseCommand parses hashcat arguments as built by the pipeline steps.
+func parseCommand(args []string, options Options) (command, error) {
+	cmd := command{attack: "0", potfile: options.Potfile, statusTimer: 10 * time.Second}
+	value := func(i *int) (string, error) {
+		if *i+1 >= len(args) {
+			return "", fmt.Errorf("option %s needs a value", args[*i])
+		}
+		*i++
+		return args[*i], nil
+	}
+
+	for i := 0; i < len(args); i++ {
+		var err error
+		switch arg := args[i]; arg {
+		case "-a", "--attack-mode":
+			cmd.attack, err = value(&i)
+		case "-m", "--hash-type":
+			cmd.mode, err = value(&i)
+		case "-r", "--rules-file":
+			var rule string
+			rule, err = value(&i)
+			cmd.rules = append(cmd.rules, rule)
+		case "--potfile-path":
+			cmd.potfile, err = value(&i)
+		case "--show":
+			cmd.show = true
+		case "--status":
+			cmd.status = true
+		case "--status-timer":
+			var seconds string
+			if seconds, err = value(&i); err == nil {
+				var n int
+				n, err = strconv.Atoi(seconds)
+				cmd.statusTimer = time.Duration(n) * time.Second
+			}
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return command{}, fmt.Errorf("option %s is not supported by the Go backend", arg)
+			}
+			cmd.positional = append(cmd.positional, arg)
+		}
+		if err != nil {
+			return command{}, err
+		}
+	}
+
+	if !Supported(cmd.mode) {
+		return command{}, fmt.Errorf("hash mode %q is not supported by the Go backend (supported: %s)", cmd.mode, strings.Join(SupportedModes(), ", "))
+	}
+	if cmd.attack != "0" && cmd.attack != "9" {
+		return command{}, fmt.Errorf("attack mode %s is not supported by the Go backend (supported: 0, 9)", cmd.attack)
+	}
+	if len(cmd.positional) == 0 {
+		return command{}, fmt.Errorf("no hashlist given")
+	}
+	if !cmd.show && len(cmd.positional) < 2 {
+		return command{}, fmt.Errorf("no wordlist given")
+	}
+	return cmd, nil
+}
+
+// Run executes a hashcat-style command with the Go backend: a straight (-a 0) or association (-a 9)
+// attack with optional -r rules, or --show. Cracked hashes are appended to a hashcat-compatible potfile.
+func Run(args []string, options Options) error {
+	cmd, err := parseCommand(args, options)
+	if err != nil {
+		return err
+	}
+	if options.Stdout == nil {
+		options.Stdout = os.Stdout
+	}
+	if options.Workers <= 0 {
+		options.Workers = runtime.NumCPU()
+	}
+
+	targets, err := loadTargets(cmd.positional[0], cmd.mode)
+	if err != nil {
+		return err
+	}
+	potted, err := ReadPotfile(cmd.potfile)
+	if err != nil {
+		return err
+	}
+	if cmd.show {
+		return show(targets, potted, options.Stdout)
+	}
+	return crack(cmd, targets, potted, options)
+}
+
+// target is one hashlist line: the digests to find and the hash as reported by --show.
+type target struct {
+	hash    string
+	digests []string
+	valid   bool
+}
+
+// digestLengths is the hex length of each mode's hashes; LM hashes may also be given as a single half.
+var digestLengths = map[string]int{ModeMD5: 32, ModeSHA1: 40, ModeNTLM: 32, ModeSHA256: 64, ModeLM: 32}
+
+// blankLMHalf is the LM hash half of an empty password, known without cracking.
+const blankLMHalf = "aad3b435b51404ee"
+
+// loadTargets reads a hashlist of plain hashes, `user:hash` lines or secretsdump lines. For LM the LM
+// field of secretsdump lines is used and each hash is split into its two independently cracked halves.
+func loadTargets(hashlist, mode string) ([]target, error) {
+	file, err := os.Open(hashlist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open hashlist %s: %w", hashlist, err)
+	}
+	defer file.Close()
+
+	var targets []target
+	skipped := 0
+	lineNumber := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		hash := utils.NormalizeHash(line)
+		if strings.Contains(line, ":") {
+			entry := utils.ParseHashlistLine(line, lineNumber)
+			hash = entry.Hash
+			if mode == ModeLM && entry.LMHash != "" {
+				hash = entry.LMHash
+			}
+		}
+
+		t := target{hash: hash, digests: []string{hash}, valid: true}
+		switch {
+		case mode == ModeLM && len(hash) == 2*len(blankLMHalf) && isHex(hash):
+			t.digests = nil
+			for _, half := range []string{hash[:16], hash[16:]} {
+				if half != blankLMHalf {
+					t.digests = append(t.digests, half)
+				}
+			}
+		case mode == ModeLM && len(hash) == len(blankLMHalf) && isHex(hash):
+		case len(hash) != digestLengths[mode] || !isHex(hash):
+			skipped++
+			t.digests, t.valid = nil, false
+		}
+		targets = append(targets, t) // Unusable lines are kept so that association hints stay aligned
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read hashlist %s: %w", hashlist, err)
+	}
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Skipped %d hashlist lines that are not valid for mode %s.\n", skipped, mode)
+	}
+	return targets, nil
+}
+
+// plain returns a target's password from the potfile, joining LM halves, and whether it is cracked.
+func (t target) plain(potted map[string]string) (string, bool) {
+	if !t.valid {
+		return "", false
+	}
+	var plain strings.Builder
+	for _, digest := range t.digests {
+		part, ok := potted[digest]
+		if !ok {
+			return "", false
+		}
+		plain.WriteString(part)
+	}
+	return plain.String(), true
+}
+
+// show prints `hash:plain` for every distinct cracked hash of the hashlist, like hashcat --show.
+func show(targets []target, potted map[string]string, stdout io.Writer) error {
+	printed := make(map[string]struct{})
+	for _, t := range targets {
+		if _, ok := printed[t.hash]; ok {
+			continue
+		}
+		plain, ok := t.plain(potted)
+		if !ok {
+			continue
+		}
+		printed[t.hash] = struct{}{}
+		if _, err := fmt.Fprintf(stdout, "%s:%s\n", t.hash, EncodePlain(plain)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// candidateBatch is a batch of words for the workers, with the hashlist line each word is tied to in association attacks.
+type candidateBatch struct {
+	words [][]byte
+	lines []int
+}
+
+// batchSize is the number of words handed to a worker at a time.
+const batchSize = 1024
+
+// crack runs the attack with a pool of workers, appending new cracks to the potfile.
+func crack(cmd command, targets []target, potted map[string]string, options Options) error {
+	h := hashers[cmd.mode]
+	rules, skippedRules, err := LoadRules(cmd.rules)
+	if err != nil {
+		return err
+	}
+	if skippedRules > 0 {
+		fmt.Fprintf(options.Stdout, "Skipped %d rules using functions the Go backend does not support.\n", skippedRules)
+	}
+
+	// Digests still to crack, overall and per hashlist line for association attacks
+	remaining := make(map[string]struct{})
+	lineDigests := make([]map[string]struct{}, len(targets))
+	for i, t := range targets {
+		lineDigests[i] = make(map[string]struct{})
+		for _, digest := range t.digests {
+			if _, ok := potted[digest]; ok {
+				continue
+			}
+			remaining[digest] = struct{}{}
+			lineDigests[i][digest] = struct{}{}
+		}
+	}
+	total := len(remaining)
+	if total == 0 {
+		fmt.Fprintln(options.Stdout, "All hashes found in potfile.")
+		return nil
+	}
+
+	potfile, err := openPotfile(cmd.potfile)
+	if err != nil {
+		return err
+	}
+	defer potfile.Close()
+
+	batches := make(chan candidateBatch, options.Workers*2)
+	results := make(chan [2]string, 64)
+	done := make(chan struct{})
+	var stopOnce sync.Once
+	stop := func() { stopOnce.Do(func() { close(done) }) }
+	var candidates atomic.Int64
+
+	// Workers hash every rule applied to every word; the remaining maps are only read here
+	var workers sync.WaitGroup
+	for w := 0; w < options.Workers; w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for batch := range batches {
+				for i, word := range batch.words {
+					wanted := remaining
+					if cmd.attack == "9" {
+						wanted = lineDigests[batch.lines[i]]
+					}
+					for _, rule := range rules {
+						candidate, ok := rule.Apply(word)
+						if !ok {
+							continue
+						}
+						digest, ok := h.digest(candidate)
+						if !ok {
+							continue
+						}
+						if _, hit := wanted[digest]; hit {
+							select {
+							case results <- [2]string{digest, string(candidate)}:
+							case <-done:
+								return
+							}
+						}
+					}
+					candidates.Add(int64(len(rules)))
+				}
+			}
+		}()
+	}
+
+	// The producer reads the wordlist, or the association hints paired with hashlist lines
+	producerErr := make(chan error, 1)
+	go func() {
+		defer close(batches)
+		producerErr <- produce(cmd, len(targets), batches, done)
+	}()
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	start := time.Now()
+	var ticker <-chan time.Time
+	if cmd.status && cmd.statusTimer > 0 {
+		t := time.NewTicker(cmd.statusTimer)
+		defer t.Stop()
+		ticker = t.C
+	}
+
+	cracked := make(map[string]struct{})
+	var writeErr error
+	for running := true; running; {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				running = false
+				break
+			}
+			digest, plain := result[0], result[1]
+			if _, duplicate := cracked[digest]; duplicate {
+				continue
+			}
+			cracked[digest] = struct{}{}
+			if err := potfile.add(digest, plain); err != nil && writeErr == nil {
+				writeErr = fmt.Errorf("failed to write potfile %s: %w", cmd.potfile, err)
+			}
+			fmt.Fprintf(options.Stdout, "%s:%s\n", digest, EncodePlain(plain))
+			if len(cracked) == total {
+				stop()
+			}
+		case <-ticker:
+			fmt.Fprintf(options.Stdout, "Status: %s, %d candidates, %d/%d hashes cracked\n",
+				time.Since(start).Round(time.Second), candidates.Load(), len(cracked), total)
+		}
+	}
+	stop()
+
+	fmt.Fprintf(options.Stdout, "Go backend (%s): %d/%d hashes cracked, %d candidates in %s\n",
+		h.name, len(cracked), total, candidates.Load(), time.Since(start).Round(time.Millisecond))
+	if err := <-producerErr; err != nil {
+		return err
+	}
+	return writeErr
+}
+
+// produce sends the words of the attack to the workers in batches until the input ends or done is closed.
+func produce(cmd command, lines int, batches chan<- candidateBatch, done <-chan struct{}) error {
+	file, err := os.Open(cmd.positional[1])
+	if err != nil {
+		return fmt.Errorf("failed to open wordlist %s: %w", cmd.positional[1], err)
+	}
+	defer file.Close()
+
+	batch := candidateBatch{}
+	send := func() bool {
+		select {
+		case batches <- batch:
+			batch = candidateBatch{}
+			return true
+		case <-done:
+			return false
+		}
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 0; scanner.Scan(); line++ {
+		if cmd.attack == "9" && line >= lines {
+			break
+		}
+		word := []byte(DecodePlain(strings.TrimRight(scanner.Text(), "\r"))) // Hashcat decodes $HEX[...] words
+		batch.words = append(batch.words, word)
+		batch.lines = append(batch.lines, line)
+		if len(batch.words) == batchSize && !send() {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read wordlist %s: %w", cmd.positional[1], err)
+	}
+	if len(batch.words) > 0 {
+		send()
+	}
+	return nil
+}
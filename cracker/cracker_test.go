@@ -0,0 +1,86 @@
+package cracker
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeLines writes lines to a file in dir and returns its path.
+func writeLines(t *testing.T, dir, name string, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		attack   string
+		mode     string
+		hashlist []string
+		words    []string
+		rules    []string
+		want     map[string]string
+	}{
+		{
+			name:     "straight attack with rules",
+			attack:   "0",
+			mode:     ModeNTLM,
+			hashlist: []string{"alice:" + NTHash("Summer2024"), NTHash("password")},
+			words:    []string{"summer", "password"},
+			rules:    []string{":", "c $2 $0 $2 $4"},
+			want:     map[string]string{NTHash("Summer2024"): "Summer2024", NTHash("password"): "password"},
+		},
+		{
+			name:     "straight attack on LM halves",
+			attack:   "0",
+			mode:     ModeLM,
+			hashlist: []string{"bob:1001:" + LMHash("password") + ":" + NTHash("password") + ":::"},
+			words:    []string{"passwor", "d"},
+			want:     map[string]string{"e52cac67419a9a22": "passwor", "4a3b108f3fa6cb6d": "d"},
+		},
+		{
+			// The invalid second line still takes the second hint, so later hints stay on their own line
+			name:     "association hints stay aligned past invalid lines",
+			attack:   "9",
+			mode:     ModeNTLM,
+			hashlist: []string{NTHash("alice1"), "not-a-hash", NTHash("carol1"), NTHash("dave1")},
+			words:    []string{"alice1", "dave1", "carol1", "alice1"},
+			want:     map[string]string{NTHash("alice1"): "alice1", NTHash("carol1"): "carol1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			args := []string{"-a", tt.attack, "-m", tt.mode,
+				writeLines(t, dir, "hashes.txt", tt.hashlist...), writeLines(t, dir, "words.txt", tt.words...)}
+			if tt.rules != nil {
+				args = append(args, "-r", writeLines(t, dir, "test.rule", tt.rules...))
+			}
+			potfile := filepath.Join(dir, "test.pot")
+
+			var stdout bytes.Buffer
+			if err := Run(args, Options{Potfile: potfile, Stdout: &stdout, Workers: 2}); err != nil {
+				t.Fatalf("Run: %v", err)
+			}
+			got, err := ReadPotfile(potfile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("cracked %v, want %v", got, tt.want)
+			}
+			for hash, plain := range tt.want {
+				if got[hash] != plain {
+					t.Errorf("cracked %s as %q, want %q", hash, got[hash], plain)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package cracker
+
+import (
+	"crypto/des"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/md4"
+)
+
+// Hash modes supported by the Go backend, numbered as in hashcat.
+const (
+	ModeMD5    = "0"
+	ModeSHA1   = "100"
+	ModeNTLM   = "1000"
+	ModeSHA256 = "1400"
+	ModeLM     = "3000"
+)
+
+// lmMaxLength is the longest candidate an LM hash half can hold.
+const lmMaxLength = 7
+
+// lmMagic is the constant DES-encrypted to produce each LM hash half.
+var lmMagic = []byte("KGS!@#$%")
+
+// hasher computes the hex digest of a candidate for one hash mode.
+type hasher struct {
+	name   string
+	digest func(candidate []byte) (string, bool)
+}
+
+var hashers = map[string]hasher{
+	ModeMD5: {"MD5", func(candidate []byte) (string, bool) {
+		sum := md5.Sum(candidate)
+		return hex.EncodeToString(sum[:]), true
+	}},
+	ModeSHA1: {"SHA1", func(candidate []byte) (string, bool) {
+		sum := sha1.Sum(candidate)
+		return hex.EncodeToString(sum[:]), true
+	}},
+	ModeNTLM: {"NTLM", func(candidate []byte) (string, bool) {
+		return NTHash(string(candidate)), true
+	}},
+	ModeSHA256: {"SHA2-256", func(candidate []byte) (string, bool) {
+		sum := sha256.Sum256(candidate)
+		return hex.EncodeToString(sum[:]), true
+	}},
+	ModeLM: {"LM", func(candidate []byte) (string, bool) {
+		if len(candidate) > lmMaxLength {
+			return "", false
+		}
+		return LMHalf(string(candidate)), true
+	}},
+}
+
+// SupportedModes returns the hash modes the Go backend can crack.
+func SupportedModes() []string {
+	modes := make([]string, 0, len(hashers))
+	for mode := range hashers {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	return modes
+}
+
+// Supported reports whether the Go backend can crack a hash mode.
+func Supported(mode string) bool {
+	_, ok := hashers[mode]
+	return ok
+}
+
+// NTHash returns the NTLM hash of a password: MD4 over its UTF-16LE encoding.
+func NTHash(password string) string {
+	h := md4.New()
+	h.Write(utf16LE(password))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// LMHalf returns the LM hash of a password of up to 7 characters, which is one half of a full LM hash.
+func LMHalf(password string) string {
+	key := make([]byte, lmMaxLength)
+	copy(key, strings.ToUpper(password))
+
+	block, err := des.NewCipher(lmDESKey(key))
+	if err != nil {
+		return ""
+	}
+	out := make([]byte, des.BlockSize)
+	block.Encrypt(out, lmMagic)
+	return hex.EncodeToString(out)
+}
+
+// LMHash returns the full LM hash of a password of up to 14 characters.
+func LMHash(password string) string {
+	upper := strings.ToUpper(password)
+	if len(upper) > 2*lmMaxLength {
+		upper = upper[:2*lmMaxLength]
+	}
+	first, second := upper, ""
+	if len(upper) > lmMaxLength {
+		first, second = upper[:lmMaxLength], upper[lmMaxLength:]
+	}
+	return LMHalf(first) + LMHalf(second)
+}
+
+// lmDESKey spreads 7 key bytes over the 8 bytes of a DES key, leaving the parity bits clear.
+func lmDESKey(key []byte) []byte {
+	return []byte{
+		key[0] & 0xfe,
+		(key[0]<<7 | key[1]>>1) & 0xfe,
+		(key[1]<<6 | key[2]>>2) & 0xfe,
+		(key[2]<<5 | key[3]>>3) & 0xfe,
+		(key[3]<<4 | key[4]>>4) & 0xfe,
+		(key[4]<<3 | key[5]>>5) & 0xfe,
+		(key[5]<<2 | key[6]>>6) & 0xfe,
+		key[6] << 1,
+	}
+}
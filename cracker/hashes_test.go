@@ -0,0 +1,59 @@
+package cracker
+
+import "testing"
+
+func TestHashers(t *testing.T) {
+	tests := []struct {
+		mode     string
+		password string
+		want     string
+		ok       bool
+	}{
+		{ModeMD5, "password", "5f4dcc3b5aa765d61d8327deb882cf99", true},
+		{ModeSHA1, "password", "5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8", true},
+		{ModeNTLM, "password", "8846f7eaee8fb117ad06bdd830b7586c", true},
+		{ModeNTLM, "", "31d6cfe0d16ae931b73c59d7e0c089c0", true},
+		{ModeSHA256, "password", "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8", true},
+		{ModeLM, "passwor", "e52cac67419a9a22", true},
+		{ModeLM, "", "aad3b435b51404ee", true},
+		{ModeLM, "password", "", false}, // Longer than one LM half
+	}
+	for _, tt := range tests {
+		got, ok := hashers[tt.mode].digest([]byte(tt.password))
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("mode %s digest(%q) = %q, %v, want %q, %v", tt.mode, tt.password, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestNTHash(t *testing.T) {
+	tests := []struct {
+		password string
+		want     string
+	}{
+		{"password", "8846f7eaee8fb117ad06bdd830b7586c"},
+		{"", "31d6cfe0d16ae931b73c59d7e0c089c0"},
+	}
+	for _, tt := range tests {
+		if got := NTHash(tt.password); got != tt.want {
+			t.Errorf("NTHash(%q) = %s, want %s", tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestLMHash(t *testing.T) {
+	tests := []struct {
+		password string
+		want     string
+	}{
+		{"password", "e52cac67419a9a224a3b108f3fa6cb6d"},
+		{"PASSWORD", "e52cac67419a9a224a3b108f3fa6cb6d"}, // LM uppercases the password
+		{"", "aad3b435b51404eeaad3b435b51404ee"},
+		{"passwor", "e52cac67419a9a22aad3b435b51404ee"}, // Seven characters leave the second half blank
+	}
+	for _, tt := range tests {
+		if got := LMHash(tt.password); got != tt.want {
+			t.Errorf("LMHash(%q) = %s, want %s", tt.password, got, tt.want)
+		}
+	}
+}
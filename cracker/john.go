@@ -0,0 +1,110 @@
+package cracker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// johnTags maps hashcat modes to the format tag John the Ripper prefixes to raw hashes in its pot file.
+// bcrypt hashes are written the same way by both tools.
+var johnTags = map[string]string{
+	ModeNTLM:   "$NT$",
+	ModeLM:     "$LM$",
+	ModeMD5:    "$dynamic_0$",
+	ModeSHA1:   "$dynamic_26$",
+	ModeSHA224: "$SHA224$",
+	ModeSHA256: "$SHA256$",
+	ModeSHA384: "$SHA384$",
+	ModeSHA512: "$SHA512$",
+}
+
+// ToJohn converts hashcat potfile entries to John the Ripper pot entries. Raw hashes are identified by
+// recomputing them from their plaintext, and full LM hashes become the two halves John stores. Entries
+// that cannot be identified, or have no John equivalent here, are kept unchanged and counted.
+func ToJohn(entries []PotfileEntry) ([]PotfileEntry, int) {
+	converted := make([]PotfileEntry, 0, len(entries))
+	unchanged := 0
+	for _, entry := range entries {
+		result := VerifyLine(entry.Line(), "")
+		if result.Status == Verified && result.Mode == ModeBcrypt {
+			converted = append(converted, entry)
+			continue
+		}
+		tag, ok := johnTags[result.Mode]
+		if result.Status != Verified || !ok {
+			converted = append(converted, entry)
+			unchanged++
+			continue
+		}
+
+		hash := strings.ToLower(entry.Hash)
+		if result.Mode == ModeLM && len(hash) == 32 {
+			plain := strings.ToUpper(entry.Plain)
+			first, second := plain, ""
+			if len(plain) > lmMaxLength {
+				first, second = plain[:lmMaxLength], plain[lmMaxLength:]
+			}
+			converted = append(converted, PotfileEntry{Hash: tag + hash[:16], Plain: first})
+			if second != "" {
+				converted = append(converted, PotfileEntry{Hash: tag + hash[16:], Plain: second})
+			}
+			continue
+		}
+		converted = append(converted, PotfileEntry{Hash: tag + hash, Plain: entry.Plain})
+	}
+	return converted, unchanged
+}
+
+// ReadJohnPot reads a John the Ripper pot file as hashcat potfile entries, stripping the format tags of
+// raw hashes. Lines with other formats are kept unchanged and counted.
+func ReadJohnPot(filename string) ([]PotfileEntry, int, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open pot file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var entries []PotfileEntry
+	unchanged := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		entry, ok := fromJohnLine(line)
+		if !ok {
+			if entry, ok = parsePotfileEntry(line); !ok {
+				return nil, 0, fmt.Errorf("invalid pot file line in %s: %q", filename, line)
+			}
+			unchanged++
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read pot file %s: %w", filename, err)
+	}
+	return entries, unchanged, nil
+}
+
+// fromJohnLine converts a John pot line holding a tagged raw hash or a bcrypt hash. The hash ends at
+// the first colon, so plaintexts may contain colons.
+func fromJohnLine(line string) (PotfileEntry, bool) {
+	separator := strings.Index(line, ":")
+	if separator <= 0 {
+		return PotfileEntry{}, false
+	}
+	hash, plain := line[:separator], DecodePlain(line[separator+1:])
+	if isBcrypt(hash) {
+		return PotfileEntry{Hash: hash, Plain: plain}, true
+	}
+	for _, tag := range johnTags {
+		if raw := strings.TrimPrefix(hash, tag); raw != hash && isHex(raw) {
+			return PotfileEntry{Hash: strings.ToLower(raw), Plain: plain}, true
+		}
+	}
+	return PotfileEntry{}, false
+}
@@ -0,0 +1,176 @@
+package cracker
+
+import (
+	"bufio"
+	"fmt"
+	"hashcat-auto/utils"
+	"os"
+	"strings"
+)
+
+// EncodePlain returns a plaintext as hashcat writes it to a potfile; see utils.EncodeHexPlain.
+func EncodePlain(plain string) string {
+	return utils.EncodeHexPlain(plain)
+}
+
+// DecodePlain reverses EncodePlain, leaving plaintexts that are not valid $HEX[...] values unchanged.
+func DecodePlain(plain string) string {
+	return utils.DecodeHexPlain(plain)
+}
+
+// ReadPotfile reads `hash:plain` lines into a map from lowercase hash to decoded plaintext.
+// A missing potfile is empty.
+func ReadPotfile(filename string) (map[string]string, error) {
+	cracked := make(map[string]string)
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return cracked, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open potfile %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if hash, plain, ok := SplitPotfileLine(strings.TrimRight(scanner.Text(), "\r")); ok {
+			cracked[hash] = plain
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read potfile %s: %w", filename, err)
+	}
+	return cracked, nil
+}
+
+// SplitPotfileLine splits a potfile line into its lowercased hash and decoded plaintext. Raw hex hashes
+// end at the first colon, so plaintexts may contain colons; other hashes, which may contain colons
+// themselves, end at the last one.
+func SplitPotfileLine(line string) (string, string, bool) {
+	entry, ok := parsePotfileEntry(line)
+	return strings.ToLower(entry.Hash), entry.Plain, ok
+}
+
+// PotfileEntry is one cracked hash of a potfile, with the hash as written and the decoded plaintext.
+type PotfileEntry struct {
+	Hash  string
+	Plain string
+}
+
+// Line formats the entry as a potfile line.
+func (e PotfileEntry) Line() string {
+	return e.Hash + ":" + EncodePlain(e.Plain)
+}
+
+// key identifies the entry for deduplication; hex hashes compare case-insensitively.
+func (e PotfileEntry) key() string {
+	if isHex(e.Hash) {
+		return strings.ToLower(e.Hash) + ":" + e.Plain
+	}
+	return e.Hash + ":" + e.Plain
+}
+
+// parsePotfileEntry splits a potfile line as SplitPotfileLine does, keeping the hash's case.
+func parsePotfileEntry(line string) (PotfileEntry, bool) {
+	separator := strings.Index(line, ":")
+	if separator <= 0 {
+		return PotfileEntry{}, false
+	}
+	if !isHex(line[:separator]) {
+		separator = strings.LastIndex(line, ":")
+	}
+	return PotfileEntry{Hash: line[:separator], Plain: DecodePlain(line[separator+1:])}, true
+}
+
+// ReadPotfileEntries reads the entries of a potfile in order, returning them with the number of lines
+// that could not be parsed.
+func ReadPotfileEntries(filename string) ([]PotfileEntry, int, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open potfile %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var entries []PotfileEntry
+	invalid := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if entry, ok := parsePotfileEntry(line); ok {
+			entries = append(entries, entry)
+		} else {
+			invalid++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read potfile %s: %w", filename, err)
+	}
+	return entries, invalid, nil
+}
+
+// DedupePotfile drops repeated entries, keeping the first occurrence of each.
+func DedupePotfile(entries []PotfileEntry) []PotfileEntry {
+	seen := make(map[string]struct{}, len(entries))
+	unique := make([]PotfileEntry, 0, len(entries))
+	for _, entry := range entries {
+		if _, ok := seen[entry.key()]; ok {
+			continue
+		}
+		seen[entry.key()] = struct{}{}
+		unique = append(unique, entry)
+	}
+	return unique
+}
+
+// WritePotfile writes entries as a potfile, replacing any existing file.
+func WritePotfile(filename string, entries []PotfileEntry) error {
+	var b strings.Builder
+	for _, entry := range entries {
+		b.WriteString(entry.Line() + "\n")
+	}
+	if err := os.WriteFile(filename, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write potfile %s: %w", filename, err)
+	}
+	return nil
+}
+
+// isHex reports whether a value is made of hex digits only.
+func isHex(value string) bool {
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return value != ""
+}
+
+// potfileWriter appends cracked hashes to a potfile.
+type potfileWriter struct {
+	file *os.File
+}
+
+// openPotfile opens a potfile for appending, creating it if needed.
+func openPotfile(filename string) (*potfileWriter, error) {
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open potfile %s: %w", filename, err)
+	}
+	return &potfileWriter{file: file}, nil
+}
+
+// add appends one `hash:plain` line.
+func (p *potfileWriter) add(hash, plain string) error {
+	_, err := fmt.Fprintf(p.file, "%s:%s\n", hash, EncodePlain(plain))
+	return err
+}
+
+// Close closes the potfile.
+func (p *potfileWriter) Close() error {
+	return p.file.Close()
+}
@@ -0,0 +1,333 @@
+package cracker
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Rule is one parsed hashcat rule line, a sequence of functions applied in order.
+type Rule struct {
+	source    string
+	functions []ruleFunction
+}
+
+// ruleFunction is one rule function with its arguments.
+type ruleFunction struct {
+	name byte
+	args []byte
+}
+
+// ruleArgs is the number of argument characters taken by each supported rule function. Functions
+// that are not listed (memory, rejection and bitwise functions) make the rule unsupported.
+var ruleArgs = map[byte]int{
+	':': 0, 'l': 0, 'u': 0, 'c': 0, 'C': 0, 't': 0, 'r': 0, 'd': 0, 'f': 0,
+	'{': 0, '}': 0, '[': 0, ']': 0, 'k': 0, 'K': 0, 'q': 0, 'E': 0,
+	'T': 1, 'D': 1, 'z': 1, 'Z': 1, '\'': 1, 'p': 1, 'y': 1, 'Y': 1,
+	'$': 1, '^': 1, '@': 1,
+	's': 2, 'i': 2, 'o': 2, 'x': 2, 'O': 2, '*': 2,
+}
+
+// maxCandidateLength matches hashcat's limit on the length of a rule-processed candidate.
+const maxCandidateLength = 256
+
+// ParseRule parses a hashcat rule line. Spaces between functions are ignored.
+func ParseRule(line string) (Rule, error) {
+	rule := Rule{source: line}
+	for i := 0; i < len(line); {
+		name := line[i]
+		if name == ' ' || name == '\t' {
+			i++
+			continue
+		}
+		count, ok := ruleArgs[name]
+		if !ok {
+			return Rule{}, fmt.Errorf("unsupported rule function %q", name)
+		}
+		if i+1+count > len(line) {
+			return Rule{}, fmt.Errorf("rule function %q is missing arguments", name)
+		}
+		rule.functions = append(rule.functions, ruleFunction{name: name, args: []byte(line[i+1 : i+1+count])})
+		i += 1 + count
+	}
+	return rule, nil
+}
+
+// LoadRules reads hashcat rule files and combines them like repeated -r options: every rule of the first
+// file followed by every rule of the next. Comments and blank lines are skipped, and unsupported rules are
+// dropped and counted. With no files the result is the single no-op rule.
+func LoadRules(files []string) ([]Rule, int, error) {
+	combined := []Rule{{source: ":"}}
+	skipped := 0
+	for _, filename := range files {
+		rules, fileSkipped, err := loadRuleFile(filename)
+		if err != nil {
+			return nil, 0, err
+		}
+		skipped += fileSkipped
+
+		var next []Rule
+		for _, first := range combined {
+			for _, second := range rules {
+				next = append(next, Rule{
+					source:    strings.TrimSpace(first.source + " " + second.source),
+					functions: append(append([]ruleFunction(nil), first.functions...), second.functions...),
+				})
+			}
+		}
+		combined = next
+	}
+	return combined, skipped, nil
+}
+
+// loadRuleFile reads one rule file, returning its supported rules and how many were skipped.
+func loadRuleFile(filename string) ([]Rule, int, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open rule file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var rules []Rule
+	skipped := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule, err := ParseRule(line)
+		if err != nil {
+			skipped++
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read rule file %s: %w", filename, err)
+	}
+	return rules, skipped, nil
+}
+
+// Apply runs the rule on a word. It returns false if a function's position is out of range, in
+// which case hashcat skips the candidate too.
+func (r Rule) Apply(word []byte) ([]byte, bool) {
+	out := append(make([]byte, 0, len(word)+8), word...)
+	for _, function := range r.functions {
+		var ok bool
+		out, ok = function.apply(out)
+		if !ok || len(out) > maxCandidateLength {
+			return nil, false
+		}
+	}
+	return out, true
+}
+
+// position decodes a rule position argument: 0-9 then A-Z for 10-35.
+func position(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), true
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10, true
+	default:
+		return 0, false
+	}
+}
+
+// apply runs one rule function.
+func (f ruleFunction) apply(w []byte) ([]byte, bool) {
+	switch f.name {
+	case ':':
+		return w, true
+	case 'l':
+		return bytes.ToLower(w), true
+	case 'u':
+		return bytes.ToUpper(w), true
+	case 'c':
+		w = bytes.ToLower(w)
+		if len(w) > 0 {
+			w[0] = toUpper(w[0])
+		}
+		return w, true
+	case 'C':
+		w = bytes.ToUpper(w)
+		if len(w) > 0 {
+			w[0] = toLower(w[0])
+		}
+		return w, true
+	case 't':
+		for i := range w {
+			w[i] = toggle(w[i])
+		}
+		return w, true
+	case 'r':
+		for i, j := 0, len(w)-1; i < j; i, j = i+1, j-1 {
+			w[i], w[j] = w[j], w[i]
+		}
+		return w, true
+	case 'd':
+		return append(w, w...), true
+	case 'f':
+		reflected := append([]byte(nil), w...)
+		for i := len(w) - 1; i >= 0; i-- {
+			reflected = append(reflected, w[i])
+		}
+		return reflected, true
+	case '{':
+		if len(w) > 1 {
+			w = append(w[1:], w[0])
+		}
+		return w, true
+	case '}':
+		if len(w) > 1 {
+			w = append([]byte{w[len(w)-1]}, w[:len(w)-1]...)
+		}
+		return w, true
+	case '[':
+		if len(w) > 0 {
+			w = w[1:]
+		}
+		return w, true
+	case ']':
+		if len(w) > 0 {
+			w = w[:len(w)-1]
+		}
+		return w, true
+	case 'k':
+		if len(w) > 1 {
+			w[0], w[1] = w[1], w[0]
+		}
+		return w, true
+	case 'K':
+		if n := len(w); n > 1 {
+			w[n-2], w[n-1] = w[n-1], w[n-2]
+		}
+		return w, true
+	case 'q':
+		doubled := make([]byte, 0, 2*len(w))
+		for _, c := range w {
+			doubled = append(doubled, c, c)
+		}
+		return doubled, true
+	case 'E':
+		w = bytes.ToLower(w)
+		for i := range w {
+			if i == 0 || w[i-1] == ' ' {
+				w[i] = toUpper(w[i])
+			}
+		}
+		return w, true
+	case '$':
+		return append(w, f.args[0]), true
+	case '^':
+		return append([]byte{f.args[0]}, w...), true
+	case '@':
+		return bytes.ReplaceAll(w, f.args[:1], nil), true
+	case 's':
+		return bytes.ReplaceAll(w, f.args[:1], f.args[1:2]), true
+	}
+
+	n, ok := position(f.args[0])
+	if !ok {
+		return nil, false
+	}
+	switch f.name {
+	case 'T':
+		if n < len(w) {
+			w[n] = toggle(w[n])
+		}
+		return w, true
+	case 'D':
+		if n < len(w) {
+			w = append(w[:n], w[n+1:]...)
+		}
+		return w, true
+	case 'z':
+		if len(w) == 0 {
+			return w, true
+		}
+		return append(bytes.Repeat(w[:1], n), w...), true
+	case 'Z':
+		if len(w) == 0 {
+			return w, true
+		}
+		return append(w, bytes.Repeat(w[len(w)-1:], n)...), true
+	case '\'':
+		if n < len(w) {
+			w = w[:n]
+		}
+		return w, true
+	case 'p':
+		return bytes.Repeat(w, n+1), true
+	case 'y':
+		if n > len(w) {
+			return nil, false
+		}
+		return append(append([]byte(nil), w[:n]...), w...), true
+	case 'Y':
+		if n > len(w) {
+			return nil, false
+		}
+		return append(w, w[len(w)-n:]...), true
+	case 'i':
+		if n > len(w) {
+			return nil, false
+		}
+		return append(w[:n], append([]byte{f.args[1]}, w[n:]...)...), true
+	case 'o':
+		if n < len(w) {
+			w[n] = f.args[1]
+		}
+		return w, true
+	}
+
+	m, ok := position(f.args[1])
+	if !ok {
+		return nil, false
+	}
+	switch f.name {
+	case 'x':
+		if n+m > len(w) {
+			return nil, false
+		}
+		return w[n : n+m], true
+	case 'O':
+		if n+m > len(w) {
+			return nil, false
+		}
+		return append(w[:n], w[n+m:]...), true
+	case '*':
+		if n < len(w) && m < len(w) {
+			w[n], w[m] = w[m], w[n]
+		}
+		return w, true
+	}
+	return nil, false
+}
+
+func toUpper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 32
+	}
+	return c
+}
+
+func toLower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 32
+	}
+	return c
+}
+
+func toggle(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 32
+	}
+	if c >= 'A' && c <= 'Z' {
+		return c + 32
+	}
+	return c
+}
@@ -0,0 +1,76 @@
+package cracker
+
+import "testing"
+
+// TestRuleApply checks each rule function against the examples in hashcat's rule-based attack documentation.
+func TestRuleApply(t *testing.T) {
+	tests := []struct {
+		rule string
+		word string
+		want string
+		ok   bool
+	}{
+		{":", "p@ssW0rd", "p@ssW0rd", true},
+		{"l", "p@ssW0rd", "p@ssw0rd", true},
+		{"u", "p@ssW0rd", "P@SSW0RD", true},
+		{"c", "p@ssW0rd", "P@ssw0rd", true},
+		{"C", "p@ssW0rd", "p@SSW0RD", true},
+		{"t", "p@ssW0rd", "P@SSw0RD", true},
+		{"T3", "p@ssW0rd", "p@sSW0rd", true},
+		{"r", "p@ssW0rd", "dr0Wss@p", true},
+		{"d", "p@ssW0rd", "p@ssW0rdp@ssW0rd", true},
+		{"p2", "p@ssW0rd", "p@ssW0rdp@ssW0rdp@ssW0rd", true},
+		{"f", "p@ssW0rd", "p@ssW0rddr0Wss@p", true},
+		{"{", "p@ssW0rd", "@ssW0rdp", true},
+		{"}", "p@ssW0rd", "dp@ssW0r", true},
+		{"$1", "p@ssW0rd", "p@ssW0rd1", true},
+		{"^1", "p@ssW0rd", "1p@ssW0rd", true},
+		{"[", "p@ssW0rd", "@ssW0rd", true},
+		{"]", "p@ssW0rd", "p@ssW0r", true},
+		{"D3", "p@ssW0rd", "p@sW0rd", true},
+		{"x04", "p@ssW0rd", "p@ss", true},
+		{"O12", "p@ssW0rd", "psW0rd", true},
+		{"i4!", "p@ssW0rd", "p@ss!W0rd", true},
+		{"o3$", "p@ssW0rd", "p@s$W0rd", true},
+		{"'6", "p@ssW0rd", "p@ssW0", true},
+		{"ss$", "p@ssW0rd", "p@$$W0rd", true},
+		{"@s", "p@ssW0rd", "p@W0rd", true},
+		{"z2", "p@ssW0rd", "ppp@ssW0rd", true},
+		{"Z2", "p@ssW0rd", "p@ssW0rddd", true},
+		{"q", "p@ssW0rd", "pp@@ssssWW00rrdd", true},
+		{"k", "p@ssW0rd", "@pssW0rd", true},
+		{"K", "p@ssW0rd", "p@ssW0dr", true},
+		{"*34", "p@ssW0rd", "p@sWs0rd", true},
+		{"y2", "p@ssW0rd", "p@p@ssW0rd", true},
+		{"Y2", "p@ssW0rd", "p@ssW0rdrd", true},
+		{"E", "p@ssW0rd w0rld", "P@ssw0rd W0rld", true},
+		{"c $2 $0 $2 $4", "summer", "Summer2024", true},
+		{"TA", "p@ssW0rd", "p@ssW0rd", true}, // Positions past the end leave the word unchanged
+		{"i9!", "p@ssW0rd", "", false},       // Inserting past the end rejects the candidate
+		{"x48", "p@ssW0rd", "", false},       // Extracting past the end rejects the candidate
+	}
+	for _, tt := range tests {
+		rule, err := ParseRule(tt.rule)
+		if err != nil {
+			t.Errorf("ParseRule(%q): %v", tt.rule, err)
+			continue
+		}
+		got, ok := rule.Apply([]byte(tt.word))
+		if string(got) != tt.want || ok != tt.ok {
+			t.Errorf("rule %q on %q = %q, %v, want %q, %v", tt.rule, tt.word, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestParseRuleErrors(t *testing.T) {
+	tests := []string{
+		"X012", // Memory functions are not supported
+		"$",    // Missing argument
+		"s1",   // Missing replacement
+	}
+	for _, line := range tests {
+		if _, err := ParseRule(line); err == nil {
+			t.Errorf("ParseRule(%q) succeeded, want an error", line)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package cracker
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Hash modes that can be verified but not cracked by the Go backend, numbered as in hashcat.
+const (
+	ModeSHA224    = "1300"
+	ModeSHA384    = "10800"
+	ModeSHA512    = "1700"
+	ModeNetNTLMv2 = "5600"
+	ModeBcrypt    = "3200"
+)
+
+// VerifyStatus is the outcome of recomputing a cracked entry's hash.
+type VerifyStatus int
+
+const (
+	Verified    VerifyStatus = iota // The password produces the hash
+	Mismatch                        // The hash type is known but the password does not produce the hash
+	Unsupported                     // The hash type could not be identified
+	OtherType                       // The password produces the hash, but as a different type than the rest of the file
+)
+
+// Verification is the result of checking one cracked entry.
+type Verification struct {
+	Line      int    // 1-based line number in the cracked file
+	Hash      string // The hash as written, without any leading username
+	Plain     string // The decoded plaintext
+	Algorithm string // The matching algorithm, or every algorithm tried for a mismatch
+	Mode      string // The hashcat mode of a verified entry
+	Status    VerifyStatus
+}
+
+// verifier recomputes one hash type.
+type verifier struct {
+	name   string
+	mode   string
+	accept func(hash string) bool
+	verify func(hash, plain string) bool
+}
+
+var verifiers = []verifier{
+	{"NTLM", ModeNTLM, hexLength(32), digestVerifier(func(plain string) string { return NTHash(plain) })},
+	{"MD5", ModeMD5, hexLength(32), sumVerifier(func(b []byte) []byte { sum := md5.Sum(b); return sum[:] })},
+	{"LM", ModeLM, func(hash string) bool { return hexLength(16)(hash) || hexLength(32)(hash) }, verifyLM},
+	{"SHA1", ModeSHA1, hexLength(40), sumVerifier(func(b []byte) []byte { sum := sha1.Sum(b); return sum[:] })},
+	{"SHA2-224", ModeSHA224, hexLength(56), sumVerifier(func(b []byte) []byte { sum := sha256.Sum224(b); return sum[:] })},
+	{"SHA2-256", ModeSHA256, hexLength(64), sumVerifier(func(b []byte) []byte { sum := sha256.Sum256(b); return sum[:] })},
+	{"SHA2-384", ModeSHA384, hexLength(96), sumVerifier(func(b []byte) []byte { sum := sha512.Sum384(b); return sum[:] })},
+	{"SHA2-512", ModeSHA512, hexLength(128), sumVerifier(func(b []byte) []byte { sum := sha512.Sum512(b); return sum[:] })},
+	{"NetNTLMv2", ModeNetNTLMv2, isNetNTLMv2, verifyNetNTLMv2},
+	{"bcrypt", ModeBcrypt, isBcrypt, func(hash, plain string) bool {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain)) == nil
+	}},
+}
+
+// VerifyModes returns the hash modes Verify can check.
+func VerifyModes() []string {
+	modes := make([]string, len(verifiers))
+	for i, v := range verifiers {
+		modes[i] = v.mode
+	}
+	return modes
+}
+
+// VerifySupported reports whether Verify can check a hash mode.
+func VerifySupported(mode string) bool {
+	for _, v := range verifiers {
+		if v.mode == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifyLine checks one `hash:plain` line, as written by hashcat --show or to a potfile. A raw hex hash
+// ends at the first colon, as in SplitPotfileLine, so plaintexts may contain colons. Other lines are split
+// at the last colon and a leading username field is skipped. With a mode only that hash type is tried;
+// otherwise every type the hash could be is tried, so a 32-character hash verifies as NTLM, MD5 or LM.
+func VerifyLine(line, mode string) Verification {
+	if entry, ok := parsePotfileEntry(line); ok && isHex(entry.Hash) {
+		if result, ok := verifyHash(entry.Hash, entry.Plain, mode); ok {
+			return result
+		}
+	}
+
+	separator := strings.LastIndex(line, ":")
+	if separator <= 0 {
+		return Verification{Status: Unsupported}
+	}
+	prefix, plain := line[:separator], DecodePlain(line[separator+1:])
+	for {
+		if result, ok := verifyHash(prefix, plain, mode); ok {
+			return result
+		}
+		next := strings.Index(prefix, ":")
+		if next < 0 {
+			return Verification{Hash: line[:separator], Plain: plain, Status: Unsupported}
+		}
+		prefix = prefix[next+1:]
+	}
+}
+
+// verifyHash checks a plaintext against every verifier accepting the hash's format. It returns false if
+// no verifier accepts the hash.
+func verifyHash(hash, plain, mode string) (Verification, bool) {
+	var tried []string
+	for _, v := range verifiers {
+		if (mode != "" && v.mode != mode) || !v.accept(hash) {
+			continue
+		}
+		if v.verify(hash, plain) {
+			return Verification{Hash: hash, Plain: plain, Algorithm: v.name, Mode: v.mode, Status: Verified}, true
+		}
+		tried = append(tried, v.name)
+	}
+	if len(tried) == 0 {
+		return Verification{}, false
+	}
+	return Verification{Hash: hash, Plain: plain, Algorithm: strings.Join(tried, "/"), Status: Mismatch}, true
+}
+
+// VerifyFile checks every non-empty line of a cracked file or potfile.
+func VerifyFile(filename, mode string) ([]Verification, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var results []Verification
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		result := VerifyLine(line, mode)
+		result.Line = number
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan file %s: %w", filename, err)
+	}
+	return results, nil
+}
+
+// MarkOtherTypes finds the hash type most entries verified as and marks verified entries of any other
+// type as OtherType, so that an MD5 entry in an NTLM potfile is caught when no mode was given. It
+// returns the mode of the common type, or "" if nothing verified.
+func MarkOtherTypes(results []Verification) string {
+	counts := make(map[string]int)
+	for _, result := range results {
+		if result.Status == Verified {
+			counts[result.Mode]++
+		}
+	}
+	common := ""
+	for _, v := range verifiers {
+		if counts[v.mode] > counts[common] {
+			common = v.mode
+		}
+	}
+	for i, result := range results {
+		if result.Status == Verified && result.Mode != common {
+			results[i].Status = OtherType
+		}
+	}
+	return common
+}
+
+// ModeName returns the algorithm name of a verifiable hash mode.
+func ModeName(mode string) string {
+	for _, v := range verifiers {
+		if v.mode == mode {
+			return v.name
+		}
+	}
+	return mode
+}
+
+// hexLength returns a check for hex hashes of the given length.
+func hexLength(length int) func(string) bool {
+	return func(hash string) bool {
+		return len(hash) == length && isHex(hash)
+	}
+}
+
+// digestVerifier compares a hex digest case-insensitively.
+func digestVerifier(digest func(plain string) string) func(hash, plain string) bool {
+	return func(hash, plain string) bool {
+		return strings.EqualFold(digest(plain), hash)
+	}
+}
+
+// sumVerifier compares a raw checksum with a hex hash.
+func sumVerifier(sum func([]byte) []byte) func(hash, plain string) bool {
+	return digestVerifier(func(plain string) string {
+		return hex.EncodeToString(sum([]byte(plain)))
+	})
+}
+
+// verifyLM checks a full LM hash or one half as stored in a potfile. LM is case-insensitive and a
+// full hash covers only the first 14 characters.
+func verifyLM(hash, plain string) bool {
+	if len(hash) == 16 {
+		return len(plain) <= lmMaxLength && strings.EqualFold(LMHalf(plain), hash)
+	}
+	return len(plain) <= 2*lmMaxLength && strings.EqualFold(LMHash(plain), hash)
+}
+
+// isBcrypt reports whether a hash is in bcrypt's $2a$/$2b$/$2x$/$2y$ modular crypt format.
+func isBcrypt(hash string) bool {
+	return len(hash) == 60 && hash[0] == '$' && hash[1] == '2' && strings.ContainsRune("abxy", rune(hash[2])) && hash[3] == '$'
+}
+
+// netNTLMv2Fields splits a NetNTLMv2 hash, USER::DOMAIN:server challenge:NTProofStr:blob.
+func netNTLMv2Fields(hash string) ([]string, bool) {
+	fields := strings.Split(hash, ":")
+	if len(fields) != 6 || fields[0] == "" || fields[1] != "" {
+		return nil, false
+	}
+	if !hexLength(16)(fields[3]) || !hexLength(32)(fields[4]) || !isHex(fields[5]) || len(fields[5])%2 != 0 {
+		return nil, false
+	}
+	return fields, true
+}
+
+// isNetNTLMv2 reports whether a hash is in hashcat's NetNTLMv2 format.
+func isNetNTLMv2(hash string) bool {
+	_, ok := netNTLMv2Fields(hash)
+	return ok
+}
+
+// verifyNetNTLMv2 recomputes the NTProofStr: HMAC-MD5 keyed with HMAC-MD5(NT hash, UPPER(user) + domain)
+// over the server challenge and blob.
+func verifyNetNTLMv2(hash, plain string) bool {
+	fields, ok := netNTLMv2Fields(hash)
+	if !ok {
+		return false
+	}
+	ntHash, _ := hex.DecodeString(NTHash(plain))
+	challenge, _ := hex.DecodeString(fields[3])
+	blob, _ := hex.DecodeString(fields[5])
+	proof, _ := hex.DecodeString(fields[4])
+
+	identity := hmac.New(md5.New, ntHash)
+	identity.Write(utf16LE(strings.ToUpper(fields[0]) + fields[2]))
+	response := hmac.New(md5.New, identity.Sum(nil))
+	response.Write(challenge)
+	response.Write(blob)
+	return hmac.Equal(response.Sum(nil), proof)
+}
+
+// utf16LE encodes a string as UTF-16LE.
+func utf16LE(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	encoded := make([]byte, 0, 2*len(units))
+	for _, unit := range units {
+		encoded = append(encoded, byte(unit), byte(unit>>8))
+	}
+	return encoded
+}
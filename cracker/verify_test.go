@@ -0,0 +1,60 @@
+package cracker
+
+import "testing"
+
+func TestVerifyLine(t *testing.T) {
+	tests := []struct {
+		name   string
+		line   string
+		mode   string
+		status VerifyStatus
+		want   string // Mode of a verified entry
+	}{
+		{"NTLM", "8846f7eaee8fb117ad06bdd830b7586c:password", "", Verified, ModeNTLM},
+		{"MD5 detected among 32-character types", "5f4dcc3b5aa765d61d8327deb882cf99:password", "", Verified, ModeMD5},
+		{"full LM hash", "e52cac67419a9a224a3b108f3fa6cb6d:PASSWORD", "", Verified, ModeLM},
+		{"LM half", "e52cac67419a9a22:passwor", ModeLM, Verified, ModeLM},
+		{"SHA1", "5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8:password", "", Verified, ModeSHA1},
+		{"SHA2-256", "5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8:password", "", Verified, ModeSHA256},
+		{"username field skipped", "alice:8846f7eaee8fb117ad06bdd830b7586c:password", "", Verified, ModeNTLM},
+		{"plaintext with colons", NTHash("pa:ss") + ":pa:ss", "", Verified, ModeNTLM},
+		{"$HEX plaintext", "8846f7eaee8fb117ad06bdd830b7586c:$HEX[70617373776f7264]", "", Verified, ModeNTLM},
+		{
+			"NetNTLMv2 example from hashcat",
+			"ADMIN::N46iSNekpT:08ca45b7d7ea58ee:88dcbe4446168966a153a0064958dac6:5c7830315c7830310000000000000b45c67103d07d7b95acd12ffa11230e0000000052920b85f78d013c31cdb3b92f5d765c783030:hashcat",
+			"", Verified, ModeNetNTLMv2,
+		},
+		{"wrong password", "8846f7eaee8fb117ad06bdd830b7586c:Password", "", Mismatch, ""},
+		{"wrong mode", "5f4dcc3b5aa765d61d8327deb882cf99:password", ModeNTLM, Mismatch, ""},
+		{"unknown hash type", "not-a-hash:password", "", Unsupported, ""},
+		{"no plaintext", "8846f7eaee8fb117ad06bdd830b7586c", "", Unsupported, ""},
+	}
+	for _, tt := range tests {
+		got := VerifyLine(tt.line, tt.mode)
+		if got.Status != tt.status || got.Mode != tt.want {
+			t.Errorf("%s: VerifyLine = status %d mode %q, want status %d mode %q", tt.name, got.Status, got.Mode, tt.status, tt.want)
+		}
+	}
+}
+
+func TestMarkOtherTypes(t *testing.T) {
+	results := []Verification{
+		VerifyLine("8846f7eaee8fb117ad06bdd830b7586c:password", ""),
+		VerifyLine("31d6cfe0d16ae931b73c59d7e0c089c0:", ""),
+		VerifyLine("5f4dcc3b5aa765d61d8327deb882cf99:password", ""),
+		VerifyLine("8846f7eaee8fb117ad06bdd830b7586c:Password", ""),
+	}
+	if common := MarkOtherTypes(results); common != ModeNTLM {
+		t.Fatalf("MarkOtherTypes = %q, want %q", common, ModeNTLM)
+	}
+	want := []VerifyStatus{Verified, Verified, OtherType, Mismatch}
+	for i, result := range results {
+		if result.Status != want[i] {
+			t.Errorf("entry %d: status %d, want %d", i, result.Status, want[i])
+		}
+	}
+
+	if common := MarkOtherTypes([]Verification{{Status: Mismatch}}); common != "" {
+		t.Errorf("MarkOtherTypes with nothing verified = %q, want \"\"", common)
+	}
+}
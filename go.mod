@@ -2,10 +2,14 @@ module hashcat-auto
 
 go 1.23.2
 
-require github.com/fatih/color v1.18.0
+require (
+	github.com/fatih/color v1.18.0
+	go.etcd.io/bbolt v1.3.11
+	golang.org/x/crypto v0.28.0
+)
 
 require (
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
-	golang.org/x/sys v0.25.0 // indirect
+	golang.org/x/sys v0.26.0 // indirect
 )
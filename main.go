@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"hashcat-auto/cmd"
 	"hashcat-auto/config"
+	"hashcat-auto/cracker"
 	"hashcat-auto/utils"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
 	"github.com/fatih/color"
 )
@@ -16,6 +18,15 @@ import (
 func validateEnvironment(hashcatPath string) error {
 	color.Yellow("Validating environment...")
 
+	// The Go backend needs neither Hashcat nor Docker; Docker is only used for CeWL
+	if hashcatPath == cmd.GoBackend {
+		color.Green("Using the Go backend.")
+		if _, err := exec.LookPath("docker"); err != nil {
+			color.Yellow("Docker is not installed, so --url (CeWL) will not work.")
+		}
+		return nil
+	}
+
 	// Check if Hashcat is installed
 	if _, err := exec.LookPath(hashcatPath); err != nil {
 		return fmt.Errorf("Hashcat not found at %s: %w", hashcatPath, err)
@@ -31,6 +42,51 @@ func validateEnvironment(hashcatPath string) error {
 	return nil
 }
 
+// builtinFallback links a file flag to the built-in list used when the configured file is missing.
+type builtinFallback struct {
+	flag    string
+	path    *string
+	builtin string
+}
+
+// applyBuiltinFallbacks replaces missing files with built-in lists written to the cache directory
+// and reports whether any replacement was made.
+func applyBuiltinFallbacks(fallbacks []builtinFallback) (bool, error) {
+	reduced := false
+	for _, fallback := range fallbacks {
+		path, used, err := utils.FileOrBuiltin(*fallback.path, fallback.builtin, config.CacheDir)
+		if err != nil {
+			return false, fmt.Errorf("failed to prepare built-in list for --%s: %w", fallback.flag, err)
+		}
+		if used {
+			color.Yellow("--%s file %q not found, using built-in %s.", fallback.flag, *fallback.path, fallback.builtin)
+			*fallback.path = path
+			reduced = true
+		}
+	}
+	return reduced, nil
+}
+
+// extractEngagement removes an --engagement flag from anywhere in the arguments, so that it works with
+// every subcommand, and falls back to the HASHCAT_AUTO_ENGAGEMENT environment variable.
+func extractEngagement(args []string) (string, []string) {
+	engagement := os.Getenv("HASHCAT_AUTO_ENGAGEMENT")
+	remaining := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || name != "engagement" {
+			remaining = append(remaining, args[i])
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		engagement = value
+	}
+	return engagement, remaining
+}
+
 func main() {
 	// Define the path to config.json
 	configPath := filepath.Join("config.json")
@@ -42,6 +98,83 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Switch to an engagement workspace if one was selected
+	engagement, args := extractEngagement(os.Args[1:])
+	os.Args = append(os.Args[:1], args...)
+	if engagement != "" {
+		if err := config.UseEngagement(engagement); err != nil {
+			color.Red("Error: %v", err)
+			os.Exit(1)
+		}
+		color.Green("Using engagement workspace %s (%s)", config.Engagement, config.EngagementDir)
+	}
+
+	// Run a subcommand if one was given
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "generate":
+			if err := cmd.RunGenerate(os.Args[2:]); err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "report":
+			if err := cmd.RunReport(os.Args[2:]); err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "export":
+			if err := cmd.RunExport(os.Args[2:]); err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "verify":
+			if err := cmd.RunVerify(os.Args[2:]); err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "potfile":
+			if err := cmd.RunPotfile(os.Args[2:]); err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "workspace":
+			if err := cmd.RunWorkspace(os.Args[2:]); err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "corpus":
+			if err := cmd.RunCorpus(os.Args[2:]); err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "history":
+			if err := cmd.RunHistory(os.Args[2:]); err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "diff":
+			if err := cmd.RunDiff(os.Args[2:]); err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+			return
+		case "retest":
+			if err := cmd.RunRetest(os.Args[2:]); err != nil {
+				color.Red("Error: %v", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	// Print the loaded configuration for debugging
 	color.Green("Loaded Configuration:")
 	color.Green("DefaultHashcatPath: %s\n", config.DefaultHashcatPath)
@@ -54,6 +187,9 @@ func main() {
 	color.Green("DefaultPassphraseRule2: %s\n", config.DefaultPassphraseRule2)
 	color.Green("DefaultDictionary: %s\n", config.DefaultDictionary)
 	color.Green("Cache Directory: %s\n", config.CacheDir)
+	if config.Engagement != "" {
+		color.Green("Engagement Potfile: %s\n", config.EngagementPotfile)
+	}
 
 	// Define command-line flags
 	hashlist := flag.String("hashlist", "", "Path to the hashlist file (REQUIRED: user:hash format)")
@@ -70,6 +206,21 @@ func main() {
 	passphraseRule2 := flag.String("passphraserule2", config.DefaultPassphraseRule2, "Path to passphrase-rule2.rule")
 	enableAdditionalWordlists := flag.Bool("enable-additional-wordlists", false, "Enable processing of additional wordlists")
 	dictionary := flag.String("dictionary", config.DefaultDictionary, "Path to the dictionary file")
+	hints := flag.String("hints", "", "Path to a CSV of per-account details keyed by username for the association attack")
+	groups := flag.String("groups", "", "Comma-separated group membership exports (ldapdomaindump, BloodHound JSON or group,member CSV) to flag cracked privileged accounts")
+	sensitiveGroups := flag.String("sensitive-groups", "", "Comma-separated extra sensitive groups besides Domain Admins and Enterprise Admins")
+	company := flag.String("company", "", "Company name for the organisation-context wordlist")
+	city := flag.String("city", "", "City or region for the organisation-context wordlist")
+	keywords := flag.String("keywords", "", "Comma-separated keywords for the organisation-context wordlist")
+	years := flag.String("years", cmd.DefaultYearRange(), "Year range for the organisation-context wordlist, e.g. 2020-2025")
+	languages := flag.String("languages", "en", "Comma-separated languages for seasons and months in the organisation-context wordlist")
+	flag.String("engagement", engagement, "Engagement workspace with its own cache, potfile and config overrides (also read from HASHCAT_AUTO_ENGAGEMENT and accepted by every subcommand)")
+	sharedPotfileWordlist := flag.Bool("shared-potfile-wordlist", false, "With --engagement, run the plaintexts of the main config's potfile as a wordlist (its hashes are never used)")
+	corpus := flag.String("corpus", config.CorpusFile, "Found-passwords corpus that collects cracked plaintexts across runs (empty disables it)")
+	corpusTop := flag.Int("corpus-top", 1000, "Run this many of the corpus's most frequently found passwords first (0 disables the step)")
+	corpusWordlist := flag.Bool("corpus-wordlist", false, "With --engagement, run the corpus's top passwords first (other clients' plaintexts are not used by default)")
+	backend := flag.String("backend", "hashcat", "Cracking backend: hashcat, or go for the built-in CPU backend (NTLM, MD5, SHA1, SHA256 and LM only)")
+	redact := flag.String("redact", "full", "Password redaction in terminal output: full, partial or hash (working files in the cache directory stay complete)")
 
 	// Parse command-line flags
 	flag.Parse()
@@ -87,6 +238,31 @@ func main() {
 		os.Exit(1)
 	}
 
+	orgContext, err := cmd.NewOrgContext(*company, *city, *keywords, *years, *languages)
+	if err != nil {
+		color.Red("Error: %v", err)
+		os.Exit(1)
+	}
+
+	redaction, err := utils.ParseRedactionMode(*redact)
+	if err != nil {
+		color.Red("Error: %v", err)
+		os.Exit(1)
+	}
+
+	switch *backend {
+	case "hashcat":
+	case "go":
+		if !cracker.Supported(*hashcatMode) {
+			color.Red("Error: the Go backend does not support mode %s (supported: %s)", *hashcatMode, strings.Join(cracker.SupportedModes(), ", "))
+			os.Exit(1)
+		}
+		*hashcatPath = cmd.GoBackend
+	default:
+		color.Red("Error: unknown backend %q (expected hashcat or go)", *backend)
+		os.Exit(1)
+	}
+
 	// Validate environment and input files
 	err = validateEnvironment(*hashcatPath)
 	if err != nil {
@@ -94,14 +270,13 @@ func main() {
 		os.Exit(1)
 	}
 
-	filesToValidate := []string{
-		*hashlist,
-		*wordlist,
-		*potfile,
-		*clemRule,
-		*rulesFull,
-		*passphrases,
-		*passphraseRule1,
+	filesToValidate := []string{*hashlist}
+
+	if *hints != "" {
+		filesToValidate = append(filesToValidate, *hints)
+	}
+	if *groups != "" {
+		filesToValidate = append(filesToValidate, cmd.SplitList(*groups)...)
 	}
 
 	if err := utils.ValidateFiles(filesToValidate); err != nil {
@@ -109,8 +284,57 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Fall back to the built-in lists for any wordlists and rules that are missing. When both passphrase
+	// rules are missing, the second becomes a no-op so the built-in rule is not applied to itself.
+	passphraseRule2Builtin := utils.BuiltinRule
+	if utils.ValidateFileExists(*passphraseRule1) != nil {
+		passphraseRule2Builtin = utils.BuiltinNoopRule
+	}
+	reduced, err := applyBuiltinFallbacks([]builtinFallback{
+		{"wordlist", wordlist, utils.BuiltinWordlist},
+		{"clemrule", clemRule, utils.BuiltinRule},
+		{"rulesfull", rulesFull, utils.BuiltinRule},
+		{"passphrases", passphrases, utils.BuiltinWordlist},
+		{"passphraserule1", passphraseRule1, utils.BuiltinRule},
+		{"passphraserule2", passphraseRule2, passphraseRule2Builtin},
+		{"dictionary", dictionary, utils.BuiltinWordlist},
+	})
+	if err != nil {
+		color.Red("Error: %v", err)
+		os.Exit(1)
+	}
+	// Engagements only run the custom potfile step when their workspace config names a potfile
+	if (*potfile != "" || config.Engagement == "") && utils.ValidateFileExists(*potfile) != nil {
+		color.Yellow("Potfile %q not found, skipping the custom potfile step.", *potfile)
+		*potfile = ""
+		reduced = true
+	}
+	sharedPotfile := ""
+	if *sharedPotfileWordlist {
+		switch {
+		case config.Engagement == "":
+			color.Red("Error: --shared-potfile-wordlist needs --engagement")
+			os.Exit(1)
+		case utils.ValidateFileExists(config.SharedPotfile) != nil:
+			color.Yellow("Shared potfile %q not found, skipping the shared potfile wordlist.", config.SharedPotfile)
+		default:
+			sharedPotfile = config.SharedPotfile
+		}
+	}
+	// Inside an engagement, other clients' plaintexts are only attacked on opt-in, as for the shared potfile
+	if config.Engagement != "" && !*corpusWordlist && *corpusTop > 0 {
+		color.Yellow("Skipping the found-passwords corpus attack inside engagement %s (add --corpus-wordlist to run it).", config.Engagement)
+		*corpusTop = 0
+	} else if config.Engagement == "" && *corpusWordlist {
+		color.Red("Error: --corpus-wordlist needs --engagement")
+		os.Exit(1)
+	}
+	if reduced {
+		color.Yellow("Running a reduced plan with built-in lists. Configure full wordlists and rules in config.json for better results.")
+	}
+
 	// Run Hashcat tasks
-	if err := cmd.ProcessHashcatTasks(*hashlist, *wordlist, *potfile, *clemRule, *rulesFull, *cewlURL, *cewlWordlist, *hashcatPath, *hashcatMode, *passphrases, *passphraseRule1, *passphraseRule2, *dictionary, *enableAdditionalWordlists); err != nil {
+	if err := cmd.ProcessHashcatTasks(*hashlist, *wordlist, *potfile, sharedPotfile, *clemRule, *rulesFull, *cewlURL, *cewlWordlist, *hashcatPath, *hashcatMode, *passphrases, *passphraseRule1, *passphraseRule2, *dictionary, *hints, *groups, *sensitiveGroups, *corpus, *corpusTop, orgContext, redaction, *enableAdditionalWordlists); err != nil {
 		color.Red("Error: %v", err)
 		return
 	}
@@ -0,0 +1,265 @@
+package report
+
+import (
+	"hashcat-auto/utils"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Count is a value and how often it was seen.
+type Count struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// PasswordStats holds pipal-style statistics over a set of cracked plaintexts.
+type PasswordStats struct {
+	Total         int     `json:"total"`
+	Blank         int     `json:"blank"`
+	Unique        int     `json:"unique"`
+	Lengths       []Count `json:"lengths"`
+	CharClasses   []Count `json:"char_classes"`
+	BaseWords     []Count `json:"base_words"`
+	Prefixes      []Count `json:"prefixes"`
+	Suffixes      []Count `json:"suffixes"`
+	Years         []Count `json:"years"`
+	Seasons       []Count `json:"seasons"`
+	KeyboardWalks []Count `json:"keyboard_walks"`
+	Masks         []Count `json:"masks"`
+	WithYear      int     `json:"with_year"`
+	WithSeason    int     `json:"with_season"`
+	WithKeyboard  int     `json:"with_keyboard_walk"`
+	TopPasswords  []Count `json:"top_passwords"`
+	AverageLength float64 `json:"average_length"`
+	MinLength     int     `json:"min_length"`
+	MaxLength     int     `json:"max_length"`
+	TopLimit      int     `json:"top_limit"`
+	Basis         string  `json:"basis,omitempty"`
+}
+
+var (
+	reportYearPattern = regexp.MustCompile(`(?:19|20)\d\d`)
+	reportLetterRun   = regexp.MustCompile(`\pL+`)
+)
+
+// minKeyboardWalk is the shortest keyboard walk counted as a pattern.
+const minKeyboardWalk = 4
+
+// AnalyzePasswords computes statistics over cracked plaintexts, keeping the top entries of each ranking.
+func AnalyzePasswords(passwords []string, top int) PasswordStats {
+	stats := PasswordStats{Total: len(passwords), TopLimit: top}
+	if len(passwords) == 0 {
+		return stats
+	}
+
+	var walks []string
+	for _, walk := range utils.KeyboardWalks() {
+		if len(walk) >= minKeyboardWalk {
+			walks = append(walks, walk)
+		}
+	}
+	sort.SliceStable(walks, func(i, j int) bool { return len(walks[i]) > len(walks[j]) }) // Prefer the longest matching walk
+	seasons := utils.SeasonWords()
+
+	unique := make(map[string]int)
+	lengths := make(map[int]int)
+	classes := make(map[string]int)
+	baseWords := make(map[string]int)
+	prefixes := make(map[string]int)
+	suffixes := make(map[string]int)
+	years := make(map[string]int)
+	seasonCounts := make(map[string]int)
+	walkCounts := make(map[string]int)
+	masks := make(map[string]int)
+
+	totalLength := 0
+	stats.MinLength = -1
+	for _, password := range passwords {
+		unique[password]++
+		length := len([]rune(password))
+		totalLength += length
+		if stats.MinLength < 0 || length < stats.MinLength {
+			stats.MinLength = length
+		}
+		if length > stats.MaxLength {
+			stats.MaxLength = length
+		}
+		lengths[length]++
+		classes[CharClass(password)]++
+		masks[Mask(password)]++
+
+		prefix, base, suffix := SplitBaseWord(password)
+		if len([]rune(base)) >= 3 {
+			baseWords[strings.ToLower(base)]++
+		}
+		if prefix != "" {
+			prefixes[prefix]++
+		}
+		if suffix != "" {
+			suffixes[suffix]++
+		}
+
+		if found := reportYearPattern.FindAllString(password, -1); len(found) > 0 {
+			stats.WithYear++
+			for _, year := range found {
+				years[year]++
+			}
+		}
+
+		lower := strings.ToLower(password)
+		if season := firstWholeWord(lower, seasons); season != "" {
+			stats.WithSeason++
+			seasonCounts[season]++
+		}
+		if walk := firstContained(lower, walks); walk != "" {
+			stats.WithKeyboard++
+			walkCounts[walk]++
+		}
+	}
+
+	stats.Unique = len(unique)
+	stats.AverageLength = float64(totalLength) / float64(len(passwords))
+	stats.TopPasswords = TopCounts(unique, top)
+	stats.Lengths = intCounts(lengths)
+	stats.CharClasses = TopCounts(classes, 0)
+	stats.BaseWords = TopCounts(baseWords, top)
+	stats.Prefixes = TopCounts(prefixes, top)
+	stats.Suffixes = TopCounts(suffixes, top)
+	stats.Years = TopCounts(years, top)
+	stats.Seasons = TopCounts(seasonCounts, top)
+	stats.KeyboardWalks = TopCounts(walkCounts, top)
+	stats.Masks = TopCounts(masks, top)
+	return stats
+}
+
+// CharClass describes which character classes a password uses, e.g. "lower+digit".
+func CharClass(password string) string {
+	var lower, upper, digit, special bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			special = true
+		}
+	}
+
+	var parts []string
+	for _, class := range []struct {
+		present bool
+		name    string
+	}{{lower, "lower"}, {upper, "upper"}, {digit, "digit"}, {special, "special"}} {
+		if class.present {
+			parts = append(parts, class.name)
+		}
+	}
+	if len(parts) == 0 {
+		return "empty"
+	}
+	return strings.Join(parts, "+")
+}
+
+// ClassCount returns how many of the lower, upper, digit and special character classes a password uses.
+func ClassCount(password string) int {
+	if class := CharClass(password); class != "empty" {
+		return len(strings.Split(class, "+"))
+	}
+	return 0
+}
+
+// Mask converts a password into a hashcat mask using ?l, ?u, ?d and ?s.
+func Mask(password string) string {
+	var builder strings.Builder
+	for _, r := range password {
+		switch {
+		case r >= 'a' && r <= 'z':
+			builder.WriteString("?l")
+		case r >= 'A' && r <= 'Z':
+			builder.WriteString("?u")
+		case r >= '0' && r <= '9':
+			builder.WriteString("?d")
+		case r <= unicode.MaxASCII:
+			builder.WriteString("?s")
+		default:
+			builder.WriteString("?b")
+		}
+	}
+	return builder.String()
+}
+
+// SplitBaseWord splits a password into its leading non-letters, the letters in between and its trailing non-letters.
+func SplitBaseWord(password string) (string, string, string) {
+	runes := []rune(password)
+	start := 0
+	for start < len(runes) && !unicode.IsLetter(runes[start]) {
+		start++
+	}
+	end := len(runes)
+	for end > start && !unicode.IsLetter(runes[end-1]) {
+		end--
+	}
+	return string(runes[:start]), string(runes[start:end]), string(runes[end:])
+}
+
+// TopCounts sorts counts by frequency (then value) and keeps the first limit entries; 0 keeps all.
+func TopCounts(counts map[string]int, limit int) []Count {
+	sorted := make([]Count, 0, len(counts))
+	for value, count := range counts {
+		sorted = append(sorted, Count{Value: value, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Value < sorted[j].Value
+	})
+	if limit > 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	return sorted
+}
+
+// intCounts returns counts keyed by an integer such as a length, ordered by that integer.
+func intCounts(counts map[int]int) []Count {
+	keys := make([]int, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+
+	sorted := make([]Count, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, Count{Value: strconv.Itoa(key), Count: counts[key]})
+	}
+	return sorted
+}
+
+// firstWholeWord returns the first run of letters in s that is one of words, or "" if none is. Matching
+// whole runs keeps short words such as "ete" from matching inside "Peter" or "Complete".
+func firstWholeWord(s string, words []string) string {
+	for _, run := range reportLetterRun.FindAllString(s, -1) {
+		for _, word := range words {
+			if run == word {
+				return word
+			}
+		}
+	}
+	return ""
+}
+
+// firstContained returns the first word in words that occurs in s, or "" if none do.
+func firstContained(s string, words []string) string {
+	for _, word := range words {
+		if strings.Contains(s, word) {
+			return word
+		}
+	}
+	return ""
+}
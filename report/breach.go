@@ -0,0 +1,96 @@
+package report
+
+import (
+	"fmt"
+	"hashcat-auto/utils"
+	"sort"
+	"strconv"
+)
+
+// BreachedAccount is a user account whose hash appears in a breached-password dataset.
+type BreachedAccount struct {
+	Account    string `json:"account"`
+	Hash       string `json:"hash"`
+	Prevalence int    `json:"prevalence"`
+	Cracked    bool   `json:"cracked"`
+}
+
+// BreachStats summarises user accounts found in a breached-password dataset.
+type BreachStats struct {
+	Dataset         string            `json:"dataset"`
+	Accounts        int               `json:"accounts"`
+	Breached        int               `json:"breached"`
+	BreachedCracked int               `json:"breached_cracked"`
+	Prevalence      []Count           `json:"prevalence"`
+	BreachedList    []BreachedAccount `json:"breached_accounts"`
+}
+
+// prevalenceBuckets groups breach counts by order of magnitude.
+var prevalenceBuckets = []struct {
+	label string
+	min   int
+}{
+	{"1,000,000+", 1000000},
+	{"10,000 - 999,999", 10000},
+	{"100 - 9,999", 100},
+	{"1 - 99", 1},
+}
+
+// AnalyzeBreaches lists the user accounts whose hash is in the dataset, most prevalent first, and
+// whether they were also cracked.
+func AnalyzeBreaches(entries []utils.HashlistEntry, breaches map[string]int, cracked map[string]string, dataset string) BreachStats {
+	stats := BreachStats{Dataset: dataset}
+	buckets := make(map[string]int)
+	for _, entry := range entries {
+		if entry.Account.Kind != utils.AccountUser || entry.Hash == "" {
+			continue
+		}
+		stats.Accounts++
+		prevalence, ok := breaches[entry.Hash]
+		if !ok {
+			continue
+		}
+		_, isCracked := cracked[entry.Hash]
+		stats.Breached++
+		if isCracked {
+			stats.BreachedCracked++
+		}
+		stats.BreachedList = append(stats.BreachedList, BreachedAccount{Account: entry.Account.Raw, Hash: entry.Hash, Prevalence: prevalence, Cracked: isCracked})
+		for _, bucket := range prevalenceBuckets {
+			if prevalence >= bucket.min {
+				buckets[bucket.label]++
+				break
+			}
+		}
+	}
+
+	for _, bucket := range prevalenceBuckets {
+		stats.Prevalence = append(stats.Prevalence, Count{Value: bucket.label, Count: buckets[bucket.label]})
+	}
+	sort.SliceStable(stats.BreachedList, func(i, j int) bool {
+		return stats.BreachedList[i].Prevalence > stats.BreachedList[j].Prevalence
+	})
+	return stats
+}
+
+// tables lays out the breached passwords section, listing at most limit accounts (0 lists all).
+func (s *BreachStats) tables(limit int) []Table {
+	summary := Table{
+		Title: "Breached passwords",
+		Note: fmt.Sprintf("%s of user accounts have a password hash found in %s; %d of them were not cracked in this run.",
+			countPercent(s.Breached, s.Accounts), s.Dataset, s.Breached-s.BreachedCracked),
+		Headers: []string{"Times seen in breaches", "Accounts", "Share"},
+	}
+	for _, bucket := range s.Prevalence {
+		summary.Rows = append(summary.Rows, []string{bucket.Value, strconv.Itoa(bucket.Count), percent(bucket.Count, s.Breached)})
+	}
+
+	accounts := Table{Title: "Accounts with breached passwords", Headers: []string{"Account", "Times seen", "Cracked"}}
+	for i, account := range s.BreachedList {
+		if limit > 0 && i >= limit {
+			break
+		}
+		accounts.Rows = append(accounts.Rows, []string{account.Account, strconv.Itoa(account.Prevalence), yesNo(account.Cracked)})
+	}
+	return []Table{summary, accounts}
+}
@@ -0,0 +1,120 @@
+package report
+
+import (
+	"hashcat-auto/utils"
+	"sort"
+	"strings"
+)
+
+// AccountSnapshot is one account's hash and cracked password in an assessment.
+type AccountSnapshot struct {
+	Account  string
+	Hash     string
+	Password string
+	Cracked  bool
+}
+
+// DiffAccount is an account present in both assessments being compared.
+type DiffAccount struct {
+	Account     string `json:"account"`
+	OldHash     string `json:"old_hash"`
+	NewHash     string `json:"new_hash"`
+	OldPassword string `json:"old_password,omitempty"`
+	NewPassword string `json:"new_password,omitempty"`
+	OldCracked  bool   `json:"old_cracked"`
+	NewCracked  bool   `json:"new_cracked"`
+}
+
+// DiffStats compares the accounts of a previous and a current assessment.
+type DiffStats struct {
+	OldAccounts     int           `json:"old_accounts"`
+	NewAccounts     int           `json:"new_accounts"`
+	OldCracked      int           `json:"old_cracked"`
+	NewCracked      int           `json:"new_cracked"`
+	NewlyCracked    []DiffAccount `json:"newly_cracked"`     // Hash changed, not cracked before, cracked now
+	NoLongerCracked []DiffAccount `json:"no_longer_cracked"` // Hash changed, cracked before and not cracked now
+	CrackedAgain    []DiffAccount `json:"cracked_again"`     // Hash changed, cracked before and cracked again
+	Unchanged       []DiffAccount `json:"unchanged"`         // Same hash in both assessments, whether cracked or not
+	UnchangedKnown  int           `json:"unchanged_known"`   // Unchanged accounts whose password is known
+	Added           []string      `json:"added"`
+	Removed         []string      `json:"removed"`
+}
+
+// CompareAssessments compares two assessments' accounts, matched by case-insensitive account name. Each
+// account is in at most one category: an unchanged hash goes to Unchanged even when it was first
+// cracked in the current assessment.
+func CompareAssessments(old, current []AccountSnapshot) DiffStats {
+	stats := DiffStats{OldAccounts: len(old), NewAccounts: len(current)}
+	previous := make(map[string]AccountSnapshot, len(old))
+	for _, snapshot := range old {
+		previous[strings.ToLower(snapshot.Account)] = snapshot
+		if snapshot.Cracked {
+			stats.OldCracked++
+		}
+	}
+
+	seen := make(map[string]bool, len(current))
+	for _, now := range current {
+		key := strings.ToLower(now.Account)
+		seen[key] = true
+		if now.Cracked {
+			stats.NewCracked++
+		}
+		before, ok := previous[key]
+		if !ok {
+			stats.Added = append(stats.Added, now.Account)
+			continue
+		}
+
+		account := DiffAccount{Account: now.Account, OldHash: before.Hash, NewHash: now.Hash,
+			OldPassword: before.Password, NewPassword: now.Password, OldCracked: before.Cracked, NewCracked: now.Cracked}
+		changed := utils.NormalizeHash(before.Hash) != utils.NormalizeHash(now.Hash)
+		switch {
+		case !changed:
+			stats.Unchanged = append(stats.Unchanged, account)
+			if before.Cracked || now.Cracked {
+				stats.UnchangedKnown++
+			}
+		case before.Cracked && now.Cracked:
+			stats.CrackedAgain = append(stats.CrackedAgain, account)
+		case before.Cracked:
+			stats.NoLongerCracked = append(stats.NoLongerCracked, account)
+		case now.Cracked:
+			stats.NewlyCracked = append(stats.NewlyCracked, account)
+		}
+	}
+	for _, snapshot := range old {
+		if !seen[strings.ToLower(snapshot.Account)] {
+			stats.Removed = append(stats.Removed, snapshot.Account)
+		}
+	}
+
+	for _, accounts := range [][]DiffAccount{stats.NewlyCracked, stats.NoLongerCracked, stats.CrackedAgain, stats.Unchanged} {
+		sort.Slice(accounts, func(i, j int) bool { return accounts[i].Account < accounts[j].Account })
+	}
+	sort.Strings(stats.Added)
+	sort.Strings(stats.Removed)
+	return stats
+}
+
+// Redacted returns a copy of the comparison with passwords masked for the given mode.
+func (s DiffStats) Redacted(mode utils.RedactionMode) DiffStats {
+	redact := func(accounts []DiffAccount) []DiffAccount {
+		masked := make([]DiffAccount, len(accounts))
+		for i, account := range accounts {
+			masked[i] = account
+			if account.OldPassword != "" {
+				masked[i].OldPassword = mode.Redact(account.OldPassword, account.OldHash)
+			}
+			if account.NewPassword != "" {
+				masked[i].NewPassword = mode.Redact(account.NewPassword, account.NewHash)
+			}
+		}
+		return masked
+	}
+	s.NewlyCracked = redact(s.NewlyCracked)
+	s.NoLongerCracked = redact(s.NoLongerCracked)
+	s.CrackedAgain = redact(s.CrackedAgain)
+	s.Unchanged = redact(s.Unchanged)
+	return s
+}
@@ -0,0 +1,178 @@
+package report
+
+import (
+	"fmt"
+	"hashcat-auto/utils"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// History patterns between consecutive passwords of one account.
+const (
+	PatternSeason    = "season or month rotation"
+	PatternIncrement = "incrementing digits"
+	PatternSameBase  = "same base word"
+	PatternUnrelated = "unrelated"
+)
+
+var reportDigitRun = regexp.MustCompile(`\d+`)
+
+// uncrackedPassword marks a history entry whose password was not cracked in the rendered tables.
+const uncrackedPassword = "?"
+
+// AccountHistory is one account's passwords from oldest to current and the patterns between them.
+// Passwords that were not cracked are empty, with false in Cracked.
+type AccountHistory struct {
+	Account   string   `json:"account"`
+	Hashes    []string `json:"hashes"`
+	Passwords []string `json:"passwords"`
+	Cracked   []bool   `json:"cracked"`
+	Patterns  []string `json:"patterns,omitempty"`
+	Reused    bool     `json:"reused"`
+}
+
+// HistoryStats summarises password changes recorded in secretsdump `_historyN` entries.
+type HistoryStats struct {
+	Accounts    int              `json:"accounts"`
+	Entries     int              `json:"entries"`
+	Cracked     int              `json:"cracked_entries"`
+	Transitions int              `json:"transitions"`
+	Patterns    []Count          `json:"patterns"`
+	Predictable int              `json:"predictable_accounts"`
+	Reused      int              `json:"reused_accounts"`
+	Histories   []AccountHistory `json:"histories"`
+}
+
+// AnalyzeHistory compares each account's consecutive cracked passwords and counts how they changed.
+// Accounts that returned to an earlier password are counted as reused.
+func AnalyzeHistory(histories []utils.PasswordHistory, cracked map[string]string) HistoryStats {
+	var stats HistoryStats
+	patterns := make(map[string]int)
+	for _, history := range histories {
+		stats.Accounts++
+		stats.Entries += len(history.History)
+		for _, entry := range history.History {
+			if _, ok := cracked[entry.Hash]; ok {
+				stats.Cracked++
+			}
+		}
+
+		chain := history.Chain()
+		account := AccountHistory{Account: history.Account.Raw}
+		seen := make(map[string]struct{})
+		predictable := false
+		for i, entry := range chain {
+			password, ok := cracked[entry.Hash]
+			account.Hashes = append(account.Hashes, entry.Hash)
+			account.Passwords = append(account.Passwords, password)
+			account.Cracked = append(account.Cracked, ok)
+			if _, ok := seen[entry.Hash]; ok {
+				account.Reused = true
+			}
+			seen[entry.Hash] = struct{}{}
+
+			if i == 0 || !ok {
+				continue
+			}
+			previous, previousCracked := cracked[chain[i-1].Hash]
+			if !previousCracked {
+				continue
+			}
+			pattern := HistoryPattern(previous, password)
+			account.Patterns = append(account.Patterns, pattern)
+			patterns[pattern]++
+			stats.Transitions++
+			if pattern != PatternUnrelated {
+				predictable = true
+			}
+		}
+
+		if predictable {
+			stats.Predictable++
+		}
+		if account.Reused {
+			stats.Reused++
+		}
+		stats.Histories = append(stats.Histories, account)
+	}
+
+	for _, pattern := range []string{PatternSeason, PatternIncrement, PatternSameBase, PatternUnrelated} {
+		stats.Patterns = append(stats.Patterns, Count{Value: pattern, Count: patterns[pattern]})
+	}
+	sort.SliceStable(stats.Histories, func(i, j int) bool {
+		return len(stats.Histories[i].Patterns) > len(stats.Histories[j].Patterns)
+	})
+	return stats
+}
+
+// HistoryPattern classifies how a password changed into the next one.
+func HistoryPattern(previous, next string) string {
+	previousCycle, nextCycle := utils.CycleWord(previous), utils.CycleWord(next)
+	switch {
+	case previousCycle != "" && nextCycle != "" && previousCycle != nextCycle:
+		return PatternSeason
+	case digitsIncremented(previous, next):
+		return PatternIncrement
+	case len([]rune(LongestWord(previous))) >= 3 && LongestWord(previous) == LongestWord(next):
+		return PatternSameBase
+	default:
+		return PatternUnrelated
+	}
+}
+
+// digitsIncremented reports whether two passwords differ only in their digits, with at least one number increased.
+func digitsIncremented(previous, next string) bool {
+	if reportDigitRun.ReplaceAllString(previous, "#") != reportDigitRun.ReplaceAllString(next, "#") {
+		return false
+	}
+	previousRuns, nextRuns := reportDigitRun.FindAllString(previous, -1), reportDigitRun.FindAllString(next, -1)
+	increased := false
+	for i := range previousRuns {
+		a, errA := strconv.ParseUint(previousRuns[i], 10, 64)
+		b, errB := strconv.ParseUint(nextRuns[i], 10, 64)
+		if errA == nil && errB == nil && b > a {
+			increased = true
+		}
+	}
+	return increased
+}
+
+// tables lays out the password history section, listing at most limit accounts (0 lists all).
+func (s *HistoryStats) tables(limit int) []Table {
+	summary := Table{
+		Title: "Password history",
+		Note: fmt.Sprintf("%d accounts with %d history entries, %d of them cracked. %d accounts changed passwords in a predictable way "+
+			"and %d went back to an earlier password.", s.Accounts, s.Entries, s.Cracked, s.Predictable, s.Reused),
+		Headers: []string{"Change between consecutive passwords", "Count", "Share"},
+	}
+	for _, pattern := range s.Patterns {
+		summary.Rows = append(summary.Rows, []string{pattern.Value, strconv.Itoa(pattern.Count), percent(pattern.Count, s.Transitions)})
+	}
+
+	accounts := Table{
+		Title:   "Password histories",
+		Note:    "Passwords from oldest to current; " + uncrackedPassword + " marks a password that was not cracked.",
+		Headers: []string{"Account", "Passwords", "Changes"},
+		Literal: []int{1},
+	}
+	for i, history := range s.Histories {
+		if limit > 0 && i >= limit {
+			break
+		}
+		changes := strings.Join(history.Patterns, ", ")
+		if history.Reused {
+			changes = strings.TrimPrefix(changes+", reused an earlier password", ", ")
+		}
+		passwords := make([]string, len(history.Passwords))
+		for j, password := range history.Passwords {
+			passwords[j] = password
+			if !history.Cracked[j] {
+				passwords[j] = uncrackedPassword
+			}
+		}
+		accounts.Rows = append(accounts.Rows, []string{history.Account, strings.Join(passwords, " → "), changes})
+	}
+	return []Table{summary, accounts}
+}
@@ -0,0 +1,128 @@
+package report
+
+import (
+	"fmt"
+	"hashcat-auto/utils"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Pair relations, from most to least severe.
+const (
+	RelationIdentical     = "identical hash"
+	RelationNear          = "near-identical password"
+	RelationSameBase      = "same base word"
+	RelationUnrelated     = "unrelated passwords"
+	RelationNotComparable = "not enough cracked to compare"
+)
+
+// AdminPair is an admin/standard account pair and how their passwords relate.
+type AdminPair struct {
+	Standard         string `json:"standard"`
+	Admin            string `json:"admin"`
+	Convention       string `json:"convention"`
+	Relation         string `json:"relation"`
+	EditDistance     int    `json:"edit_distance,omitempty"`
+	StandardPassword string `json:"standard_password,omitempty"`
+	AdminPassword    string `json:"admin_password,omitempty"`
+	standardHash     string
+	adminHash        string
+}
+
+// PairStats summarises admin/standard account pairs found in a hashlist.
+type PairStats struct {
+	MaxEditDistance int         `json:"max_edit_distance"`
+	Pairs           []AdminPair `json:"pairs"`
+	Flagged         int         `json:"flagged"`
+}
+
+// AnalyzePairs compares the hashes and cracked passwords of each admin/standard pair. Passwords within
+// maxDistance edits of each other, or sharing a base word, are flagged alongside identical hashes.
+func AnalyzePairs(pairs []utils.AccountPair, cracked map[string]string, maxDistance int) PairStats {
+	stats := PairStats{MaxEditDistance: maxDistance}
+	for _, pair := range pairs {
+		adminPair := AdminPair{Standard: pair.Standard.Account.Raw, Admin: pair.Admin.Account.Raw, Convention: pair.Convention,
+			standardHash: pair.Standard.Hash, adminHash: pair.Admin.Hash}
+		standardPassword, standardCracked := cracked[pair.Standard.Hash]
+		adminPassword, adminCracked := cracked[pair.Admin.Hash]
+		adminPair.StandardPassword = standardPassword
+		adminPair.AdminPassword = adminPassword
+
+		switch {
+		case pair.Standard.Hash != "" && pair.Standard.Hash == pair.Admin.Hash:
+			adminPair.Relation = RelationIdentical
+		case !standardCracked || !adminCracked:
+			adminPair.Relation = RelationNotComparable
+		default:
+			adminPair.EditDistance = EditDistance(standardPassword, adminPassword)
+			standardBase := LongestWord(standardPassword)
+			switch {
+			case adminPair.EditDistance <= maxDistance:
+				adminPair.Relation = RelationNear
+			case len([]rune(standardBase)) >= 3 && standardBase == LongestWord(adminPassword):
+				adminPair.Relation = RelationSameBase
+			default:
+				adminPair.Relation = RelationUnrelated
+			}
+		}
+
+		if adminPair.Relation == RelationIdentical || adminPair.Relation == RelationNear || adminPair.Relation == RelationSameBase {
+			stats.Flagged++
+		}
+		stats.Pairs = append(stats.Pairs, adminPair)
+	}
+	return stats
+}
+
+// LongestWord returns the longest run of letters in a password, lowercased.
+func LongestWord(password string) string {
+	longest := ""
+	for _, word := range strings.FieldsFunc(strings.ToLower(password), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if len(word) > len(longest) {
+			longest = word
+		}
+	}
+	return longest
+}
+
+// EditDistance returns the Levenshtein distance between two strings, counted in runes.
+func EditDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
+
+// tables lays out the admin account pairing section.
+func (s *PairStats) tables() []Table {
+	table := Table{
+		Title: "Admin and standard account pairs",
+		Note: fmt.Sprintf("Admin accounts matched to the same person's standard account. Identical hashes, passwords "+
+			"within %d edits and shared base words are flagged (%d of %d pairs).", s.MaxEditDistance, s.Flagged, len(s.Pairs)),
+		Headers: []string{"Standard account", "Admin account", "Relation", "Standard password", "Admin password"},
+		Literal: []int{3, 4},
+	}
+	for _, pair := range s.Pairs {
+		relation := pair.Relation
+		if pair.Relation == RelationNear {
+			relation += " (" + strconv.Itoa(pair.EditDistance) + " edits)"
+		}
+		table.Rows = append(table.Rows, []string{pair.Standard, pair.Admin, relation, pair.StandardPassword, pair.AdminPassword})
+	}
+	return []Table{table}
+}
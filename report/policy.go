@@ -0,0 +1,150 @@
+package report
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Policy describes a password policy to check cracked passwords against.
+type Policy struct {
+	MinLength   int      `json:"min_length"`
+	MinClasses  int      `json:"min_classes"`
+	BannedWords []string `json:"banned_words,omitempty"`
+}
+
+// TimeBucket counts compliant and non-compliant passwords cracked within a time range.
+type TimeBucket struct {
+	Label        string `json:"label"`
+	Compliant    int    `json:"compliant"`
+	NonCompliant int    `json:"non_compliant"`
+}
+
+// PolicyStats summarises how cracked passwords fared against a password policy.
+type PolicyStats struct {
+	Policy           Policy       `json:"policy"`
+	Evaluated        int          `json:"evaluated"`
+	Compliant        int          `json:"compliant"`
+	TrivialCompliant int          `json:"trivial_compliant"`
+	TooShort         int          `json:"too_short"`
+	TooFewClasses    int          `json:"too_few_classes"`
+	Banned           int          `json:"banned"`
+	BannedWords      []Count      `json:"banned_words_found"`
+	TimeToCrack      []TimeBucket `json:"time_to_crack,omitempty"`
+}
+
+// CrackTime is a cracked password and how long after the start of the run it was cracked.
+type CrackTime struct {
+	Password string
+	Elapsed  time.Duration
+}
+
+// timeBuckets are the upper bounds of the time-to-crack distribution; the last bucket is open-ended.
+var timeBuckets = []struct {
+	label string
+	limit time.Duration
+}{
+	{"Already cracked / < 1 minute", time.Minute},
+	{"< 10 minutes", 10 * time.Minute},
+	{"< 1 hour", time.Hour},
+	{"< 6 hours", 6 * time.Hour},
+	{"< 24 hours", 24 * time.Hour},
+	{">= 24 hours", 0},
+}
+
+// leetReplacer undoes common character substitutions before checking banned words.
+var leetReplacer = strings.NewReplacer("@", "a", "4", "a", "0", "o", "1", "i", "!", "i", "3", "e", "$", "s", "5", "s", "7", "t")
+
+// Violations returns whether a password is too short, uses too few character classes, and
+// which banned word it contains (after undoing common leet substitutions), if any.
+func (p Policy) Violations(password string) (bool, bool, string) {
+	tooShort := len([]rune(password)) < p.MinLength
+	tooFewClasses := ClassCount(password) < p.MinClasses
+
+	// Banned words are normalised like the password, so that "acme1" or "p@ss" can still match
+	normalised := leetReplacer.Replace(strings.ToLower(password))
+	for _, word := range p.BannedWords {
+		if word != "" && strings.Contains(normalised, leetReplacer.Replace(strings.ToLower(word))) {
+			return tooShort, tooFewClasses, word
+		}
+	}
+	return tooShort, tooFewClasses, ""
+}
+
+// Compliant reports whether a password satisfies the policy.
+func (p Policy) Compliant(password string) bool {
+	tooShort, tooFewClasses, banned := p.Violations(password)
+	return !tooShort && !tooFewClasses && banned == ""
+}
+
+// IsTrivialPattern reports whether a password is a capitalised word followed only by digits and
+// symbols, such as Summer2024!, which meets complexity rules without adding real strength.
+func IsTrivialPattern(password string) bool {
+	prefix, base, suffix := SplitBaseWord(password)
+	if prefix != "" || suffix == "" || base == "" {
+		return false
+	}
+	for i, r := range []rune(base) {
+		if (i == 0 && !unicode.IsUpper(r)) || (i > 0 && !unicode.IsLower(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// AnalyzePolicy checks every cracked password against the policy. When crack times are given, it also
+// buckets compliant and non-compliant passwords by how long they took to crack.
+func AnalyzePolicy(policy Policy, passwords []string, crackTimes []CrackTime) PolicyStats {
+	for i, word := range policy.BannedWords {
+		policy.BannedWords[i] = strings.ToLower(strings.TrimSpace(word))
+	}
+	stats := PolicyStats{Policy: policy, Evaluated: len(passwords)}
+
+	bannedCounts := make(map[string]int)
+	for _, password := range passwords {
+		tooShort, tooFewClasses, banned := policy.Violations(password)
+		if tooShort {
+			stats.TooShort++
+		}
+		if tooFewClasses {
+			stats.TooFewClasses++
+		}
+		if banned != "" {
+			stats.Banned++
+			bannedCounts[banned]++
+		}
+		if !tooShort && !tooFewClasses && banned == "" {
+			stats.Compliant++
+			if IsTrivialPattern(password) {
+				stats.TrivialCompliant++
+			}
+		}
+	}
+	stats.BannedWords = TopCounts(bannedCounts, 0)
+
+	if len(crackTimes) > 0 {
+		stats.TimeToCrack = make([]TimeBucket, len(timeBuckets))
+		for i, bucket := range timeBuckets {
+			stats.TimeToCrack[i].Label = bucket.label
+		}
+		for _, crack := range crackTimes {
+			i := bucketIndex(crack.Elapsed)
+			if policy.Compliant(crack.Password) {
+				stats.TimeToCrack[i].Compliant++
+			} else {
+				stats.TimeToCrack[i].NonCompliant++
+			}
+		}
+	}
+	return stats
+}
+
+// bucketIndex returns the time bucket an elapsed crack time falls into.
+func bucketIndex(elapsed time.Duration) int {
+	for i, bucket := range timeBuckets {
+		if bucket.limit == 0 || elapsed < bucket.limit {
+			return i
+		}
+	}
+	return len(timeBuckets) - 1
+}
@@ -0,0 +1,56 @@
+package report
+
+import (
+	"fmt"
+	"hashcat-auto/utils"
+	"strings"
+)
+
+// PrivilegedAccount is a cracked account belonging to a sensitive group.
+type PrivilegedAccount struct {
+	Account  string   `json:"account"`
+	Groups   []string `json:"groups"`
+	Hash     string   `json:"hash"`
+	Password string   `json:"password"`
+}
+
+// PrivilegedStats summarises cracked accounts in sensitive groups.
+type PrivilegedStats struct {
+	SensitiveGroups []string            `json:"sensitive_groups"`
+	Privileged      int                 `json:"privileged_accounts"`
+	Cracked         []PrivilegedAccount `json:"cracked"`
+}
+
+// AnalyzePrivileged finds the hashlist's user accounts that belong to a sensitive group and lists those that were cracked.
+func AnalyzePrivileged(entries []utils.HashlistEntry, cracked map[string]string, memberships utils.GroupMemberships, sensitive []string) PrivilegedStats {
+	stats := PrivilegedStats{SensitiveGroups: sensitive}
+	for _, entry := range entries {
+		if entry.Account.Kind != utils.AccountUser {
+			continue
+		}
+		groups := memberships.SensitiveGroups(entry.Account, sensitive)
+		if len(groups) == 0 {
+			continue
+		}
+		stats.Privileged++
+		if password, ok := cracked[entry.Hash]; ok {
+			stats.Cracked = append(stats.Cracked, PrivilegedAccount{Account: entry.Account.Raw, Groups: groups, Hash: entry.Hash, Password: password})
+		}
+	}
+	return stats
+}
+
+// tables lays out the privileged accounts section.
+func (s *PrivilegedStats) tables() []Table {
+	table := Table{
+		Title: "Cracked privileged accounts",
+		Note: fmt.Sprintf("%d of %d accounts in sensitive groups (%s) were cracked.",
+			len(s.Cracked), s.Privileged, strings.Join(s.SensitiveGroups, ", ")),
+		Headers: []string{"Account", "Sensitive groups", "Password"},
+		Literal: []int{2},
+	}
+	for _, account := range s.Cracked {
+		table.Rows = append(table.Rows, []string{account.Account, strings.Join(account.Groups, ", "), account.Password})
+	}
+	return []Table{table}
+}
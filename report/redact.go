@@ -0,0 +1,108 @@
+package report
+
+import "hashcat-auto/utils"
+
+// Redacted returns a copy of the report with every plaintext password masked according to mode.
+// The report itself is left untouched so that other outputs can be written in full.
+func (r *Report) Redacted(mode utils.RedactionMode) *Report {
+	redacted := *r
+	redacted.Redaction = mode
+	if mode == utils.RedactFull {
+		return &redacted
+	}
+
+	redacted.Passwords.TopPasswords = make([]Count, len(r.Passwords.TopPasswords))
+	for i, count := range r.Passwords.TopPasswords {
+		redacted.Passwords.TopPasswords[i] = Count{Value: mode.Redact(count.Value, ""), Count: count.Count}
+	}
+
+	// Base words with prefixes or suffixes, and masks, give most passwords away: mask them, or drop them
+	// when only hashes may be shown
+	for _, counts := range []*[]Count{&redacted.Passwords.BaseWords, &redacted.Passwords.Prefixes,
+		&redacted.Passwords.Suffixes, &redacted.Passwords.Masks} {
+		if mode == utils.RedactHash {
+			*counts = nil
+			continue
+		}
+		masked := make([]Count, len(*counts))
+		for i, count := range *counts {
+			masked[i] = Count{Value: mode.Redact(count.Value, ""), Count: count.Count}
+		}
+		*counts = masked
+	}
+
+	if r.Reuse != nil {
+		reuse := *r.Reuse
+		reuse.Clusters = make([]ReuseCluster, len(r.Reuse.Clusters))
+		for i, cluster := range r.Reuse.Clusters {
+			if cluster.Cracked {
+				cluster.Password = mode.Redact(cluster.Password, cluster.Hash)
+			}
+			reuse.Clusters[i] = cluster
+		}
+		redacted.Reuse = &reuse
+	}
+
+	if r.Pairs != nil {
+		pairs := *r.Pairs
+		pairs.Pairs = make([]AdminPair, len(r.Pairs.Pairs))
+		for i, pair := range r.Pairs.Pairs {
+			if pair.StandardPassword != "" {
+				pair.StandardPassword = mode.Redact(pair.StandardPassword, pair.standardHash)
+			}
+			if pair.AdminPassword != "" {
+				pair.AdminPassword = mode.Redact(pair.AdminPassword, pair.adminHash)
+			}
+			pairs.Pairs[i] = pair
+		}
+		redacted.Pairs = &pairs
+	}
+
+	if r.Privileged != nil {
+		redacted.Privileged = r.Privileged.Redacted(mode)
+	}
+
+	if r.Accounts != nil {
+		accounts := *r.Accounts
+		accounts.Accounts = make([]CrackedAccount, len(r.Accounts.Accounts))
+		for i, account := range r.Accounts.Accounts {
+			account.Password = mode.Redact(account.Password, account.Hash)
+			accounts.Accounts[i] = account
+		}
+		redacted.Accounts = &accounts
+	}
+
+	if r.History != nil {
+		history := *r.History
+		history.Histories = make([]AccountHistory, len(r.History.Histories))
+		for i, account := range r.History.Histories {
+			passwords := make([]string, len(account.Passwords))
+			for j, password := range account.Passwords {
+				passwords[j] = password
+				if account.Cracked[j] {
+					passwords[j] = mode.Redact(password, account.Hashes[j])
+				}
+			}
+			account.Passwords = passwords
+			history.Histories[i] = account
+		}
+		redacted.History = &history
+	}
+	return &redacted
+}
+
+// Redacted returns a copy of the privileged accounts with their passwords masked according to mode.
+func (s *PrivilegedStats) Redacted(mode utils.RedactionMode) *PrivilegedStats {
+	redacted := *s
+	redacted.Cracked = make([]PrivilegedAccount, len(s.Cracked))
+	for i, account := range s.Cracked {
+		account.Password = mode.Redact(account.Password, account.Hash)
+		redacted.Cracked[i] = account
+	}
+	return &redacted
+}
+
+// redacted reports whether the report's passwords are masked.
+func (r *Report) redacted() bool {
+	return r.Redaction != "" && r.Redaction != utils.RedactFull
+}
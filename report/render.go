@@ -0,0 +1,268 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"hashcat-auto/utils"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Report is the full analysis written by the report subcommand.
+type Report struct {
+	Title      string              `json:"title"`
+	Source     string              `json:"source"`
+	Generated  time.Time           `json:"generated"`
+	Passwords  PasswordStats       `json:"passwords"`
+	Policy     *PolicyStats        `json:"policy,omitempty"`
+	Reuse      *ReuseStats         `json:"reuse,omitempty"`
+	Pairs      *PairStats          `json:"admin_pairs,omitempty"`
+	Privileged *PrivilegedStats    `json:"privileged,omitempty"`
+	Accounts   *AccountStats       `json:"cracked_accounts,omitempty"`
+	History    *HistoryStats       `json:"password_history,omitempty"`
+	Breaches   *BreachStats        `json:"breaches,omitempty"`
+	Redaction  utils.RedactionMode `json:"redaction,omitempty"`
+}
+
+// Table is one titled table of a rendered report.
+type Table struct {
+	Title   string
+	Note    string
+	Headers []string
+	Rows    [][]string
+	Literal []int // Columns holding literal values such as passwords or masks
+}
+
+// Tables lays out the report as the tables shared by the Markdown and HTML renderers.
+func (r *Report) Tables() []Table {
+	stats := r.Passwords
+	total := stats.Total
+
+	tables := []Table{{
+		Title:   "Summary",
+		Note:    stats.Basis,
+		Headers: []string{"Metric", "Value"},
+		Rows: [][]string{
+			{"Cracked passwords", strconv.Itoa(total)},
+			{"Blank passwords", strconv.Itoa(stats.Blank)},
+			{"Unique passwords", strconv.Itoa(stats.Unique)},
+			{"Average length", fmt.Sprintf("%.1f", stats.AverageLength)},
+			{"Shortest / longest", fmt.Sprintf("%d / %d", stats.MinLength, stats.MaxLength)},
+			{"Containing a year", countPercent(stats.WithYear, total)},
+			{"Containing a season", countPercent(stats.WithSeason, total)},
+			{"Containing a keyboard walk", countPercent(stats.WithKeyboard, total)},
+		},
+	}}
+
+	tables = append(tables,
+		countTable("Length distribution", "Length", stats.Lengths, total),
+		countTable("Character classes", "Classes", stats.CharClasses, total),
+		countTable("Top passwords", "Password", stats.TopPasswords, total),
+	)
+	if r.Redaction != utils.RedactHash {
+		tables = append(tables,
+			countTable("Top base words", "Base word", stats.BaseWords, total),
+			countTable("Top prefixes", "Prefix", stats.Prefixes, total),
+			countTable("Top suffixes", "Suffix", stats.Suffixes, total),
+		)
+	}
+	tables = append(tables,
+		countTable("Years", "Year", stats.Years, total),
+		countTable("Seasons", "Season", stats.Seasons, total),
+		countTable("Keyboard walks", "Walk", stats.KeyboardWalks, total),
+	)
+	if r.Redaction != utils.RedactHash {
+		tables = append(tables, countTable("Top masks", "Mask", stats.Masks, total))
+	}
+
+	if r.Policy != nil {
+		tables = append(tables, r.Policy.tables()...)
+	}
+	if r.Reuse != nil {
+		tables = append(tables, r.Reuse.tables(stats.TopLimit)...)
+	}
+	if r.Pairs != nil {
+		tables = append(tables, r.Pairs.tables()...)
+	}
+	if r.Privileged != nil {
+		tables = append(tables, r.Privileged.tables()...)
+	}
+	if r.Accounts != nil {
+		tables = append(tables, r.Accounts.tables()...)
+	}
+	if r.History != nil {
+		tables = append(tables, r.History.tables(stats.TopLimit)...)
+	}
+	if r.Breaches != nil {
+		tables = append(tables, r.Breaches.tables(stats.TopLimit)...)
+	}
+	return tables
+}
+
+// tables lays out the policy compliance section.
+func (p *PolicyStats) tables() []Table {
+	policy := p.Policy
+	banned := "none"
+	if len(policy.BannedWords) > 0 {
+		banned = strings.Join(policy.BannedWords, ", ")
+	}
+
+	tables := []Table{{
+		Title: "Password policy compliance",
+		Note: fmt.Sprintf("Policy: at least %d characters, at least %d character classes, banned words: %s.",
+			policy.MinLength, policy.MinClasses, banned),
+		Headers: []string{"Metric", "Value"},
+		Rows: [][]string{
+			{"Cracked passwords evaluated", strconv.Itoa(p.Evaluated)},
+			{"Policy-compliant", countPercent(p.Compliant, p.Evaluated)},
+			{"Compliant only via trivial pattern (Capital + word + digits/symbols)", countPercent(p.TrivialCompliant, p.Evaluated)},
+			{"Shorter than minimum length", countPercent(p.TooShort, p.Evaluated)},
+			{"Too few character classes", countPercent(p.TooFewClasses, p.Evaluated)},
+			{"Containing a banned word", countPercent(p.Banned, p.Evaluated)},
+		},
+	}}
+	tables = append(tables, countTable("Banned words found", "Banned word", p.BannedWords, p.Evaluated))
+
+	if len(p.TimeToCrack) > 0 {
+		table := Table{
+			Title:   "Time to crack",
+			Note:    "Time from the start of the run until the password was cracked.",
+			Headers: []string{"Time to crack", "Compliant", "Non-compliant"},
+		}
+		for _, bucket := range p.TimeToCrack {
+			table.Rows = append(table.Rows, []string{bucket.Label, strconv.Itoa(bucket.Compliant), strconv.Itoa(bucket.NonCompliant)})
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// isLiteral reports whether column i holds literal values.
+func (t Table) isLiteral(i int) bool {
+	for _, column := range t.Literal {
+		if column == i {
+			return true
+		}
+	}
+	return false
+}
+
+// countTable builds a table of counts with their share of total.
+func countTable(title, header string, counts []Count, total int) Table {
+	table := Table{Title: title, Headers: []string{header, "Count", "Share"}, Literal: []int{0}}
+	for _, count := range counts {
+		table.Rows = append(table.Rows, []string{count.Value, strconv.Itoa(count.Count), percent(count.Count, total)})
+	}
+	return table
+}
+
+// countPercent formats a count with its share of total.
+func countPercent(count, total int) string {
+	return fmt.Sprintf("%d (%s)", count, percent(count, total))
+}
+
+// percent formats part as a percentage of total.
+func percent(part, total int) string {
+	if total == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
+
+// WriteJSON writes the report as indented JSON.
+func WriteJSON(w io.Writer, r *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteMarkdown writes the report as Markdown tables.
+func WriteMarkdown(w io.Writer, r *Report) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", r.Title)
+	fmt.Fprintf(&b, "Source: `%s`  \nGenerated: %s\n", r.Source, r.Generated.Format(time.RFC1123))
+	if r.redacted() {
+		fmt.Fprintf(&b, "\nPasswords: redacted (%s)\n", r.Redaction)
+	}
+
+	for _, table := range r.Tables() {
+		fmt.Fprintf(&b, "\n## %s\n\n", table.Title)
+		if table.Note != "" {
+			fmt.Fprintf(&b, "%s\n\n", table.Note)
+		}
+		if len(table.Rows) == 0 {
+			b.WriteString("_None found._\n")
+			continue
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(table.Headers, " | "))
+		fmt.Fprintf(&b, "|%s\n", strings.Repeat(" --- |", len(table.Headers)))
+		for _, row := range table.Rows {
+			cells := make([]string, len(row))
+			for i, cell := range row {
+				cells[i] = markdownCell(cell, table.isLiteral(i))
+			}
+			fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCell escapes a table cell, wrapping literal values in a code span so that passwords
+// containing Markdown characters render as typed.
+func markdownCell(value string, literal bool) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	if !literal || value == "" {
+		return value
+	}
+	if strings.Contains(value, "`") {
+		return "`` " + value + " ``"
+	}
+	return "`" + value + "`"
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Report.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; }
+th { background: #f0f0f0; }
+td { font-family: monospace; }
+</style>
+</head>
+<body>
+<h1>{{.Report.Title}}</h1>
+<p>Source: <code>{{.Report.Source}}</code><br>Generated: {{.Generated}}{{if .Redaction}}<br>Passwords: redacted ({{.Redaction}}){{end}}</p>
+{{range .Tables}}
+<h2>{{.Title}}</h2>
+{{if .Note}}<p>{{.Note}}</p>{{end}}
+{{if .Rows}}<table>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>{{else}}<p><em>None found.</em></p>{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes the report as a standalone HTML page.
+func WriteHTML(w io.Writer, r *Report) error {
+	redaction := ""
+	if r.redacted() {
+		redaction = string(r.Redaction)
+	}
+	return htmlTemplate.Execute(w, struct {
+		Report    *Report
+		Generated string
+		Redaction string
+		Tables    []Table
+	}{r, r.Generated.Format(time.RFC1123), redaction, r.Tables()})
+}
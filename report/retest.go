@@ -0,0 +1,48 @@
+package report
+
+import (
+	"hashcat-auto/utils"
+	"sort"
+	"strings"
+)
+
+// RetestStats says which accounts compromised in a previous assessment changed their password.
+type RetestStats struct {
+	Compromised int      `json:"compromised"`        // Accounts in the baseline
+	Unchanged   []string `json:"unchanged"`          // Same hash as when compromised
+	Changed     []string `json:"changed"`            // Hash changed since the baseline
+	Missing     []string `json:"missing"`            // Not in the new hashlist
+	Remediated  float64  `json:"remediated_percent"` // Share of present accounts that changed their password
+}
+
+// CheckBaseline compares a new hashlist against a baseline of compromised accounts, matched by
+// case-insensitive account name. Nothing is cracked: the new hashes are fingerprinted with the key.
+func CheckBaseline(baseline utils.Baseline, key []byte, entries []utils.HashlistEntry) RetestStats {
+	current := make(map[string]utils.HashlistEntry, len(entries))
+	for _, entry := range entries {
+		if entry.Account.Kind != utils.AccountHistory {
+			current[strings.ToLower(entry.Account.Raw)] = entry
+		}
+	}
+
+	stats := RetestStats{Compromised: len(baseline.Accounts)}
+	for _, account := range baseline.Accounts {
+		entry, ok := current[strings.ToLower(account.Account)]
+		switch {
+		case !ok:
+			stats.Missing = append(stats.Missing, account.Account)
+		case utils.BaselineFingerprint(key, account.Account, entry.Hash) == account.Fingerprint:
+			stats.Unchanged = append(stats.Unchanged, entry.Account.Raw)
+		default:
+			stats.Changed = append(stats.Changed, entry.Account.Raw)
+		}
+	}
+	if present := len(stats.Unchanged) + len(stats.Changed); present > 0 {
+		stats.Remediated = float64(len(stats.Changed)) * 100 / float64(present)
+	}
+
+	sort.Strings(stats.Unchanged)
+	sort.Strings(stats.Changed)
+	sort.Strings(stats.Missing)
+	return stats
+}
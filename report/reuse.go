@@ -0,0 +1,112 @@
+package report
+
+import (
+	"hashcat-auto/utils"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReuseCluster is a group of accounts sharing one hash, and therefore one password.
+type ReuseCluster struct {
+	Hash     string   `json:"hash"`
+	Accounts []string `json:"accounts"`
+	Cracked  bool     `json:"cracked"`
+	Password string   `json:"password,omitempty"`
+}
+
+// ReuseStats summarises password reuse across the user accounts of a hashlist.
+type ReuseStats struct {
+	Accounts        int            `json:"accounts"`
+	UniqueHashes    int            `json:"unique_hashes"`
+	SharedAccounts  int            `json:"shared_accounts"`
+	CrackedClusters int            `json:"cracked_clusters"`
+	Sizes           []Count        `json:"cluster_sizes"`
+	Clusters        []ReuseCluster `json:"clusters"`
+}
+
+// AnalyzeReuse groups user accounts by identical hash, whether or not the hash was cracked.
+// Machine, built-in and history accounts are left out; clusters are ordered largest first.
+func AnalyzeReuse(entries []utils.HashlistEntry, cracked map[string]string) ReuseStats {
+	var stats ReuseStats
+	byHash := make(map[string][]string)
+	for _, entry := range entries {
+		if entry.Account.Kind != utils.AccountUser || entry.Hash == "" {
+			continue
+		}
+		stats.Accounts++
+		byHash[entry.Hash] = append(byHash[entry.Hash], entry.Account.Raw)
+	}
+	stats.UniqueHashes = len(byHash)
+
+	sizes := make(map[int]int)
+	for hash, accounts := range byHash {
+		if len(accounts) < 2 {
+			continue
+		}
+		password, ok := cracked[hash]
+		stats.Clusters = append(stats.Clusters, ReuseCluster{Hash: hash, Accounts: accounts, Cracked: ok, Password: password})
+		stats.SharedAccounts += len(accounts)
+		sizes[len(accounts)]++
+		if ok {
+			stats.CrackedClusters++
+		}
+	}
+
+	sort.Slice(stats.Clusters, func(i, j int) bool {
+		if len(stats.Clusters[i].Accounts) != len(stats.Clusters[j].Accounts) {
+			return len(stats.Clusters[i].Accounts) > len(stats.Clusters[j].Accounts)
+		}
+		return stats.Clusters[i].Hash < stats.Clusters[j].Hash
+	})
+	stats.Sizes = intCounts(sizes)
+	return stats
+}
+
+// tables lays out the password reuse section, listing at most limit clusters (0 lists all).
+func (s *ReuseStats) tables(limit int) []Table {
+	tables := []Table{{
+		Title:   "Password reuse",
+		Note:    "Accounts sharing an identical hash share the same password, whether or not it was cracked.",
+		Headers: []string{"Metric", "Value"},
+		Rows: [][]string{
+			{"User accounts", strconv.Itoa(s.Accounts)},
+			{"Unique hashes", strconv.Itoa(s.UniqueHashes)},
+			{"Accounts sharing a password", countPercent(s.SharedAccounts, s.Accounts)},
+			{"Shared-password clusters", strconv.Itoa(len(s.Clusters))},
+			{"Clusters with a cracked password", strconv.Itoa(s.CrackedClusters)},
+		},
+	}}
+
+	sizeTable := Table{Title: "Reuse cluster sizes", Headers: []string{"Accounts per cluster", "Clusters"}}
+	for _, size := range s.Sizes {
+		sizeTable.Rows = append(sizeTable.Rows, []string{size.Value, strconv.Itoa(size.Count)})
+	}
+	tables = append(tables, sizeTable)
+
+	clusterTable := Table{Title: "Largest shared-password clusters", Headers: []string{"Hash", "Accounts", "Cracked password", "Members"}, Literal: []int{0, 2}}
+	for i, cluster := range s.Clusters {
+		if limit > 0 && i >= limit {
+			break
+		}
+		password := "(not cracked)"
+		if cluster.Cracked {
+			password = cluster.Password
+		}
+		clusterTable.Rows = append(clusterTable.Rows, []string{cluster.Hash, strconv.Itoa(len(cluster.Accounts)), password, joinAccounts(cluster.Accounts)})
+	}
+	return append(tables, clusterTable)
+}
+
+// maxListedAccounts caps how many account names are listed in a single table cell.
+const maxListedAccounts = 10
+
+// joinAccounts lists account names for a table cell, summarising long lists.
+func joinAccounts(accounts []string) string {
+	sorted := append([]string(nil), accounts...)
+	sort.Strings(sorted)
+	if len(sorted) > maxListedAccounts {
+		return strings.Join(sorted[:maxListedAccounts], ", ") + " and " + strconv.Itoa(len(sorted)-maxListedAccounts) + " more"
+	}
+	return strings.Join(sorted, ", ")
+}
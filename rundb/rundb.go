@@ -0,0 +1,184 @@
+package rundb
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// runsBucket holds one JSON RunRecord per run, keyed by run ID.
+var runsBucket = []byte("runs")
+
+// Run summarises one pipeline run.
+type Run struct {
+	ID             string    `json:"id"`
+	Engagement     string    `json:"engagement,omitempty"`
+	Started        time.Time `json:"started"`
+	Finished       time.Time `json:"finished"`
+	Hashlist       string    `json:"hashlist"`
+	HashlistSHA256 string    `json:"hashlist_sha256"`
+	Mode           string    `json:"mode"`
+	Backend        string    `json:"backend"`
+	Accounts       int       `json:"accounts"`
+	Cracked        int       `json:"cracked"`
+	Status         string    `json:"status"` // "completed" or "failed"
+	Error          string    `json:"error,omitempty"`
+}
+
+// Account is one hashlist account of a run.
+type Account struct {
+	Name string `json:"name"`
+	Hash string `json:"hash"`
+}
+
+// Step is one finished pipeline step of a run.
+type Step struct {
+	Name     string    `json:"name"`
+	Finished time.Time `json:"finished"`
+	Cracked  int       `json:"cracked"`
+}
+
+// Crack is one hash cracked during a run, with the accounts that use it.
+type Crack struct {
+	Time     time.Time `json:"time"`
+	Step     string    `json:"step"`
+	Hash     string    `json:"hash"`
+	Password string    `json:"password"`
+	Accounts []string  `json:"accounts"`
+}
+
+// RunRecord is everything recorded about a run.
+type RunRecord struct {
+	Run      Run       `json:"run"`
+	Accounts []Account `json:"accounts"`
+	Steps    []Step    `json:"steps"`
+	Cracks   []Crack   `json:"cracks"`
+}
+
+// RunCrack is a crack found by a query, with the run it belongs to.
+type RunCrack struct {
+	RunID string
+	Crack
+}
+
+// DB is the run history database.
+type DB struct {
+	bolt *bolt.DB
+}
+
+// Open opens or creates the run history database. It fails rather than waits if another process has it open.
+func Open(path string) (*DB, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open run history %s: %w", path, err)
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(runsBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialise run history %s: %w", path, err)
+	}
+	return &DB{bolt: db}, nil
+}
+
+// Close closes the database.
+func (db *DB) Close() error {
+	return db.bolt.Close()
+}
+
+// SaveRun stores a run, replacing any earlier record with the same ID.
+func (db *DB) SaveRun(record RunRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode run %s: %w", record.Run.ID, err)
+	}
+	return db.bolt.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(runsBucket).Put([]byte(record.Run.ID), data)
+	})
+}
+
+// Record returns the full record of a run.
+func (db *DB) Record(id string) (*RunRecord, error) {
+	var record *RunRecord
+	err := db.bolt.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(runsBucket).Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("run %s not found", id)
+		}
+		record = &RunRecord{}
+		return json.Unmarshal(data, record)
+	})
+	return record, err
+}
+
+// Runs returns the runs of an engagement, or of every engagement when it is empty, oldest first.
+func (db *DB) Runs(engagement string) ([]Run, error) {
+	var runs []Run
+	err := db.each(func(record RunRecord) {
+		if engagement == "" || record.Run.Engagement == engagement {
+			runs = append(runs, record.Run)
+		}
+	})
+	return runs, err
+}
+
+// CracksInStep returns the cracks made by a pipeline step, optionally limited to one run.
+func (db *DB) CracksInStep(step, runID string) ([]RunCrack, error) {
+	return db.cracks(runID, func(crack Crack) bool { return crack.Step == step })
+}
+
+// FindPasswords returns the cracks whose password matches a pattern, optionally limited to one run.
+func (db *DB) FindPasswords(pattern *regexp.Regexp, runID string) ([]RunCrack, error) {
+	return db.cracks(runID, func(crack Crack) bool { return pattern.MatchString(crack.Password) })
+}
+
+// StepNames returns every step name recorded, sorted.
+func (db *DB) StepNames() ([]string, error) {
+	seen := make(map[string]struct{})
+	err := db.each(func(record RunRecord) {
+		for _, step := range record.Steps {
+			seen[step.Name] = struct{}{}
+		}
+	})
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, err
+}
+
+// cracks returns the cracks accepted by match, across all runs or in one run.
+func (db *DB) cracks(runID string, match func(Crack) bool) ([]RunCrack, error) {
+	var found []RunCrack
+	err := db.each(func(record RunRecord) {
+		if runID != "" && record.Run.ID != runID {
+			return
+		}
+		for _, crack := range record.Cracks {
+			if match(crack) {
+				found = append(found, RunCrack{RunID: record.Run.ID, Crack: crack})
+			}
+		}
+	})
+	return found, err
+}
+
+// each calls fn for every run in ID order, which is start order.
+func (db *DB) each(fn func(RunRecord)) error {
+	return db.bolt.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(runsBucket).ForEach(func(key, data []byte) error {
+			var record RunRecord
+			if err := json.Unmarshal(data, &record); err != nil {
+				return fmt.Errorf("failed to decode run %s: %w", key, err)
+			}
+			fn(record)
+			return nil
+		})
+	})
+}
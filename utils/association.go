@@ -0,0 +1,46 @@
+package utils
+
+import "strings"
+
+// AccountHints returns the hint words for an account's association attack: its username, its base words
+// and any imported hints for that username, keyed in lowercase as returned by LoadAccountHints.
+func AccountHints(account Account, imported map[string][]string) []string {
+	hints := []string{account.Username}
+	seen := map[string]struct{}{account.Username: {}}
+	for _, words := range [][]string{account.BaseWords(), imported[strings.ToLower(account.Username)]} {
+		for _, word := range words {
+			if _, ok := seen[word]; !ok {
+				seen[word] = struct{}{}
+				hints = append(hints, word)
+			}
+		}
+	}
+	return hints
+}
+
+// AssociationRounds turns per-line hint lists into hint files for hashcat's association attack (-a 9).
+// Every round has exactly one hint per hashlist line so it stays aligned with the hashlist; round N uses
+// each line's Nth hint, repeating the line's first hint once it runs out.
+func AssociationRounds(lineHints [][]string) [][]string {
+	maxHints := 0
+	for _, hints := range lineHints {
+		if len(hints) > maxHints {
+			maxHints = len(hints)
+		}
+	}
+
+	rounds := make([][]string, 0, maxHints)
+	for round := 0; round < maxHints; round++ {
+		lines := make([]string, len(lineHints))
+		for i, hints := range lineHints {
+			switch {
+			case round < len(hints):
+				lines[i] = hints[round]
+			case len(hints) > 0:
+				lines[i] = hints[0]
+			}
+		}
+		rounds = append(rounds, lines)
+	}
+	return rounds
+}
@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// userAccountControl flags used for enrichment.
+const (
+	uacAccountDisable      = 0x0002
+	uacDontExpirePassword  = 0x10000
+	filetimeNever          = 9223372036854775807
+	filetimeEpochOffsetSec = 11644473600 // Seconds between 1601-01-01 and 1970-01-01
+)
+
+// ADAttributes are Active Directory attributes of an account relevant to reporting.
+type ADAttributes struct {
+	Enabled              bool      `json:"enabled"`
+	PasswordNeverExpires bool      `json:"password_never_expires"`
+	PwdLastSet           time.Time `json:"pwd_last_set,omitempty"`
+	LastLogon            time.Time `json:"last_logon,omitempty"`
+	Description          string    `json:"description,omitempty"`
+}
+
+// PasswordAge returns how long ago the password was last set, or 0 if unknown.
+func (a ADAttributes) PasswordAge(now time.Time) time.Duration {
+	if a.PwdLastSet.IsZero() {
+		return 0
+	}
+	return now.Sub(a.PwdLastSet)
+}
+
+// LoadADAttributes reads ldapdomaindump domain_users.json files or ldapsearch LDIF output and
+// returns the attributes of each account keyed by lowercase sAMAccountName.
+func LoadADAttributes(files []string) (map[string]ADAttributes, error) {
+	attributes := make(map[string]ADAttributes)
+	for _, filename := range files {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+		}
+
+		var records []map[string][]string
+		if trimmed := bytes.TrimSpace(content); bytes.HasPrefix(trimmed, []byte("[")) {
+			records, err = parseLdapDumpRecords(trimmed)
+		} else {
+			records, err = parseLDIF(content)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+		}
+
+		for _, record := range records {
+			name := firstValue(record, "samaccountname")
+			if name == "" {
+				continue
+			}
+			attributes[strings.ToLower(name)] = adAttributesFromRecord(record)
+		}
+	}
+	return attributes, nil
+}
+
+// AttachADAttributes links loaded attributes to the hashlist entries of the same accounts.
+func AttachADAttributes(entries []HashlistEntry, attributes map[string]ADAttributes) {
+	for i := range entries {
+		if attrs, ok := attributes[strings.ToLower(entries[i].Account.Username)]; ok {
+			entries[i].AD = &attrs
+		}
+	}
+}
+
+// adAttributesFromRecord converts raw attribute values (keyed by lowercase name) into ADAttributes.
+func adAttributesFromRecord(record map[string][]string) ADAttributes {
+	var attrs ADAttributes
+	uac, err := strconv.ParseInt(firstValue(record, "useraccountcontrol"), 10, 64)
+	if err == nil {
+		attrs.Enabled = uac&uacAccountDisable == 0
+		attrs.PasswordNeverExpires = uac&uacDontExpirePassword != 0
+	} else {
+		// ldapdomaindump may store the flags by name instead
+		flags := strings.ToUpper(strings.Join(record["useraccountcontrol"], ","))
+		attrs.Enabled = !strings.Contains(flags, "ACCOUNTDISABLE")
+		attrs.PasswordNeverExpires = strings.Contains(flags, "DONT_EXPIRE_PASSWD")
+	}
+	attrs.PwdLastSet = parseADTime(firstValue(record, "pwdlastset"))
+	attrs.LastLogon = parseADTime(firstValue(record, "lastlogontimestamp"))
+	if lastLogon := parseADTime(firstValue(record, "lastlogon")); lastLogon.After(attrs.LastLogon) {
+		attrs.LastLogon = lastLogon
+	}
+	attrs.Description = firstValue(record, "description")
+	return attrs
+}
+
+// adTimeLayouts are the textual timestamp formats used by ldapdomaindump and LDAP generalized time.
+var adTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999-07:00",
+	"2006-01-02 15:04:05-07:00",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	"20060102150405.0Z",
+	"20060102150405Z",
+}
+
+// parseADTime parses a Windows FILETIME integer or a textual timestamp. Zero and "never" values return the zero time.
+func parseADTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	if filetime, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if filetime <= 0 || filetime == filetimeNever {
+			return time.Time{}
+		}
+		seconds := filetime/10000000 - filetimeEpochOffsetSec
+		return time.Unix(seconds, (filetime%10000000)*100).UTC()
+	}
+	for _, layout := range adTimeLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			if parsed.Year() <= 1601 {
+				return time.Time{}
+			}
+			return parsed.UTC()
+		}
+	}
+	return time.Time{}
+}
+
+// firstValue returns the first value of an attribute, or "" if it is missing.
+func firstValue(record map[string][]string, name string) string {
+	if values := record[name]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// parseLdapDumpRecords reads ldapdomaindump objects into attribute maps keyed by lowercase name.
+func parseLdapDumpRecords(content []byte) ([]map[string][]string, error) {
+	var objects []ldapDumpObject
+	if err := json.Unmarshal(content, &objects); err != nil {
+		return nil, err
+	}
+
+	records := make([]map[string][]string, 0, len(objects))
+	for _, object := range objects {
+		record := make(map[string][]string)
+		for name, raw := range object.Attributes {
+			var values []any
+			if err := json.Unmarshal(raw, &values); err != nil {
+				var value any
+				if err := json.Unmarshal(raw, &value); err != nil {
+					continue
+				}
+				values = []any{value}
+			}
+			for _, value := range values {
+				switch v := value.(type) {
+				case string:
+					record[strings.ToLower(name)] = append(record[strings.ToLower(name)], v)
+				case float64:
+					record[strings.ToLower(name)] = append(record[strings.ToLower(name)], strconv.FormatInt(int64(v), 10))
+				}
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// parseLDIF reads LDIF entries into attribute maps keyed by lowercase name, decoding base64 values
+// and joining continuation lines.
+func parseLDIF(content []byte) ([]map[string][]string, error) {
+	var records []map[string][]string
+	record := make(map[string][]string)
+	var lines []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.HasPrefix(line, " ") && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, line := range append(lines, "") {
+		if line == "" {
+			if len(record) > 0 {
+				records = append(records, record)
+				record = make(map[string][]string)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		if encoded, ok := strings.CutPrefix(value, ":"); ok {
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+			if err != nil {
+				return nil, fmt.Errorf("invalid base64 value for %s: %w", name, err)
+			}
+			value = string(decoded)
+		}
+		record[strings.ToLower(name)] = append(record[strings.ToLower(name)], strings.TrimSpace(value))
+	}
+	return records, nil
+}
+
+// FilterEnabled returns the entries whose Active Directory attributes mark them as enabled.
+// Entries without loaded attributes are dropped.
+func FilterEnabled(entries []HashlistEntry) []HashlistEntry {
+	var enabled []HashlistEntry
+	for _, entry := range entries {
+		if entry.AD != nil && entry.AD.Enabled {
+			enabled = append(enabled, entry)
+		}
+	}
+	return enabled
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// baselineKeySize is the length in bytes of a re-test baseline key.
+const baselineKeySize = 32
+
+// baselineKeyCheck is fingerprinted with the key so that a wrong key is detected before checking.
+const baselineKeyCheck = "hashcat-auto re-test baseline"
+
+// BaselineAccount is a compromised account of a re-test baseline. The hash is only kept as a keyed
+// fingerprint of the account name and hash.
+type BaselineAccount struct {
+	Account     string `json:"account"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// Baseline records which accounts an assessment compromised, for checking on a re-test whether their
+// passwords changed. It holds no plaintexts or hashes, so it can be kept after the engagement data is
+// destroyed.
+type Baseline struct {
+	Created    time.Time         `json:"created"`
+	Engagement string            `json:"engagement,omitempty"`
+	Hashlist   string            `json:"hashlist"`
+	KeyCheck   string            `json:"key_check"`
+	Accounts   []BaselineAccount `json:"accounts"`
+}
+
+// BaselineFingerprint returns the keyed fingerprint of an account's hash. The account name is part of
+// the fingerprint, so accounts sharing a password cannot be linked without the key.
+func BaselineFingerprint(key []byte, account, hash string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.ToLower(strings.TrimSpace(account))))
+	mac.Write([]byte{0})
+	mac.Write([]byte(NormalizeHash(hash)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewBaseline builds a baseline of the cracked accounts of a hashlist. Password history entries and
+// accounts without a hash are left out.
+func NewBaseline(key []byte, entries []HashlistEntry, cracked map[string]string) Baseline {
+	baseline := Baseline{Created: time.Now(), KeyCheck: baselineKeyFingerprint(key)}
+	for _, entry := range entries {
+		if entry.Account.Kind == AccountHistory || entry.Hash == "" {
+			continue
+		}
+		if _, ok := cracked[entry.Hash]; !ok {
+			continue
+		}
+		baseline.Accounts = append(baseline.Accounts, BaselineAccount{
+			Account:     entry.Account.Raw,
+			Fingerprint: BaselineFingerprint(key, entry.Account.Raw, entry.Hash),
+		})
+	}
+	return baseline
+}
+
+// CheckKey reports whether key is the key the baseline was created with.
+func (b Baseline) CheckKey(key []byte) error {
+	if !hmac.Equal([]byte(b.KeyCheck), []byte(baselineKeyFingerprint(key))) {
+		return fmt.Errorf("the key does not match the baseline")
+	}
+	return nil
+}
+
+// baselineKeyFingerprint returns the value stored in a baseline to recognise its key.
+func baselineKeyFingerprint(key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(baselineKeyCheck))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// LoadBaseline reads a baseline file.
+func LoadBaseline(filename string) (Baseline, error) {
+	var baseline Baseline
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return baseline, fmt.Errorf("failed to read baseline %s: %w", filename, err)
+	}
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return baseline, fmt.Errorf("failed to decode baseline %s: %w", filename, err)
+	}
+	return baseline, nil
+}
+
+// SaveBaseline writes a baseline file.
+func SaveBaseline(filename string, baseline Baseline) error {
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	if err := os.WriteFile(filename, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("failed to write baseline %s: %w", filename, err)
+	}
+	return nil
+}
+
+// ReadBaselineKey reads a hex-encoded baseline key.
+func ReadBaselineKey(filename string) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key %s: %w", filename, err)
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != baselineKeySize {
+		return nil, fmt.Errorf("invalid key in %s: expected %d hex-encoded bytes", filename, baselineKeySize)
+	}
+	return key, nil
+}
+
+// LoadOrCreateBaselineKey reads a baseline key, creating a random one if the file does not exist. It
+// also reports whether the key was created.
+func LoadOrCreateBaselineKey(filename string) ([]byte, bool, error) {
+	if _, err := os.Stat(filename); err == nil {
+		key, err := ReadBaselineKey(filename)
+		return key, false, err
+	}
+	key := make([]byte, baselineKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, false, fmt.Errorf("failed to generate key: %w", err)
+	}
+	if dir := filepath.Dir(filename); dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, false, fmt.Errorf("failed to create key directory %s: %w", dir, err)
+		}
+	}
+	if err := os.WriteFile(filename, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+		return nil, false, fmt.Errorf("failed to write key %s: %w", filename, err)
+	}
+	return key, true, nil
+}
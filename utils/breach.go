@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// breachReadSize is how much of the breach file is read to find one line; lines are about 45 bytes.
+const breachReadSize = 512
+
+// BreachFile is a local hash:count dataset sorted by hash, such as the Pwned Passwords NTLM or SHA1
+// "ordered by hash" downloads. Lookups binary-search the file on disk without loading it.
+type BreachFile struct {
+	file *os.File
+	size int64
+}
+
+// OpenBreachFile opens a sorted hash:count file for lookups.
+func OpenBreachFile(filename string) (*BreachFile, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breach file %s: %w", filename, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to stat breach file %s: %w", filename, err)
+	}
+	return &BreachFile{file: file, size: info.Size()}, nil
+}
+
+// Close closes the breach file.
+func (b *BreachFile) Close() error {
+	return b.file.Close()
+}
+
+// Lookup returns how often a hash appears in the dataset, or 0 if it is not in it.
+func (b *BreachFile) Lookup(hash string) (int, error) {
+	target := strings.ToUpper(strings.TrimSpace(hash))
+	low, high := int64(0), b.size
+	for low < high {
+		middle := low + (high-low)/2
+		start, err := b.lineStart(middle)
+		if err != nil {
+			return 0, err
+		}
+		if start >= high {
+			high = middle
+			continue
+		}
+		line, next, err := b.readLine(start)
+		if err != nil {
+			return 0, err
+		}
+
+		lineHash, count, _ := strings.Cut(line, ":")
+		switch compare := strings.Compare(strings.ToUpper(lineHash), target); {
+		case compare == 0:
+			prevalence, err := strconv.Atoi(strings.TrimSpace(count))
+			if err != nil {
+				return 0, fmt.Errorf("invalid count in breach file line %q: %w", line, err)
+			}
+			return prevalence, nil
+		case compare < 0:
+			low = next
+		default:
+			high = middle
+		}
+	}
+	return 0, nil
+}
+
+// lineStart returns the offset of the first line starting at or after offset.
+func (b *BreachFile) lineStart(offset int64) (int64, error) {
+	if offset == 0 {
+		return 0, nil
+	}
+	position := offset - 1
+	buffer := make([]byte, breachReadSize)
+	for position < b.size {
+		n, err := b.file.ReadAt(buffer, position)
+		if err != nil && err != io.EOF {
+			return 0, fmt.Errorf("failed to read breach file: %w", err)
+		}
+		if i := bytes.IndexByte(buffer[:n], '\n'); i >= 0 {
+			return position + int64(i) + 1, nil
+		}
+		if n == 0 {
+			break
+		}
+		position += int64(n)
+	}
+	return b.size, nil
+}
+
+// readLine reads the line starting at offset without its line ending, and the offset of the next line.
+func (b *BreachFile) readLine(offset int64) (string, int64, error) {
+	var line []byte
+	buffer := make([]byte, breachReadSize)
+	position := offset
+	for position < b.size {
+		n, err := b.file.ReadAt(buffer, position)
+		if err != nil && err != io.EOF {
+			return "", 0, fmt.Errorf("failed to read breach file: %w", err)
+		}
+		if i := bytes.IndexByte(buffer[:n], '\n'); i >= 0 {
+			line = append(line, buffer[:i]...)
+			return strings.TrimRight(string(line), "\r"), position + int64(i) + 1, nil
+		}
+		if n == 0 {
+			break
+		}
+		line = append(line, buffer[:n]...)
+		position += int64(n)
+	}
+	return strings.TrimRight(string(line), "\r"), b.size, nil
+}
+
+// LookupBreaches looks up every distinct hash of entries in the breach file and returns the prevalence
+// of those found, keyed by hash.
+func LookupBreaches(entries []HashlistEntry, filename string) (map[string]int, error) {
+	breachFile, err := OpenBreachFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer breachFile.Close()
+
+	breaches := make(map[string]int)
+	checked := make(map[string]struct{})
+	for _, entry := range entries {
+		if entry.Hash == "" {
+			continue
+		}
+		if _, ok := checked[entry.Hash]; ok {
+			continue
+		}
+		checked[entry.Hash] = struct{}{}
+
+		prevalence, err := breachFile.Lookup(entry.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if prevalence > 0 {
+			breaches[entry.Hash] = prevalence
+		}
+	}
+	return breaches, nil
+}
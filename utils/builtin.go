@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//go:embed builtin
+var builtinFiles embed.FS
+
+// Built-in lists compiled into the binary, used when configured files are missing.
+const (
+	BuiltinWordlist = "builtin_wordlist.txt"  // Top passwords, seasons/months and keyboard walks combined
+	BuiltinRule     = "builtin_suffixes.rule" // Common capitalisation, digit, symbol and year suffix rules
+	BuiltinNoopRule = "builtin_noop.rule"     // hashcat's `:` rule, which leaves every word unchanged
+	builtinDir      = "builtin"               // Directory holding the embedded files
+	builtinRuleFile = "common_suffixes.rule"  // Embedded rule file backing BuiltinRule
+)
+
+// builtinWordlistParts are the embedded lists concatenated into BuiltinWordlist.
+var builtinWordlistParts = []string{"top_passwords.txt", "seasons_months.txt", "keyboard_walks.txt"}
+
+// WriteBuiltin writes the named built-in list (BuiltinWordlist, BuiltinRule or BuiltinNoopRule) into dir
+// and returns its path.
+func WriteBuiltin(name, dir string) (string, error) {
+	var parts, lines []string
+	switch name {
+	case BuiltinWordlist:
+		parts = builtinWordlistParts
+	case BuiltinRule:
+		parts = []string{builtinRuleFile}
+	case BuiltinNoopRule:
+		lines = []string{":"}
+	default:
+		return "", fmt.Errorf("unknown built-in list %s", name)
+	}
+
+	for _, part := range parts {
+		content, err := builtinFiles.ReadFile(builtinDir + "/" + part)
+		if err != nil {
+			return "", fmt.Errorf("failed to read built-in list %s: %w", part, err)
+		}
+		lines = append(lines, strings.Split(strings.TrimSpace(string(content)), "\n")...)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := WriteToFile(path, lines); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// FileOrBuiltin returns path if the file exists, otherwise it writes the named built-in list into dir
+// and returns that path instead. The boolean result reports whether the built-in list was used.
+func FileOrBuiltin(path, builtin, dir string) (string, bool, error) {
+	if path != "" {
+		if _, err := os.Stat(path); err == nil {
+			return path, false, nil
+		}
+	}
+	builtinPath, err := WriteBuiltin(builtin, dir)
+	if err != nil {
+		return "", false, err
+	}
+	return builtinPath, true, nil
+}
+
+// KeyboardWalks returns the built-in keyboard walk patterns in lowercase.
+func KeyboardWalks() []string {
+	content, err := builtinFiles.ReadFile(builtinDir + "/keyboard_walks.txt")
+	if err != nil {
+		return nil
+	}
+	var walks []string
+	for _, walk := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		walk = strings.ToLower(strings.TrimSpace(walk))
+		if walk != "" && !contains(walks, walk) {
+			walks = append(walks, walk)
+		}
+	}
+	return walks
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -23,18 +24,22 @@ func RunCommandToFile(command string, args []string, outputFile string) error {
 	return nil
 }
 
-// RunCommand runs a command and outputs the results to the terminal.
+// RunCommand runs a command and outputs the results to the terminal, masked per SetTerminalRedaction.
 func RunCommand(command string, args []string) error {
 	cmd := exec.Command(command, args...)
-	cmd.Stdout = os.Stdout
+	stdout, flush := TerminalOutput()
+	defer flush()
+	cmd.Stdout = stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
-// RunShellCommand runs a shell command and outputs its results.
+// RunShellCommand runs a shell command and outputs its results, masked per SetTerminalRedaction.
 func RunShellCommand(command string) error {
 	cmd := exec.Command("bash", "-c", command)
-	cmd.Stdout = os.Stdout
+	stdout, flush := TerminalOutput()
+	defer flush()
+	cmd.Stdout = stdout
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
@@ -42,3 +47,16 @@ func RunShellCommand(command string) error {
 	}
 	return nil
 }
+
+// ExitCode returns the exit status carried by an error from RunCommand, 0 for a nil error
+// and -1 when the command could not be started or was killed by a signal.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
@@ -0,0 +1,180 @@
+package utils
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// corpusDate is the layout of the last-seen date in the corpus file.
+const corpusDate = "2006-01-02"
+
+// CorpusEntry is one plaintext of the found-passwords corpus.
+type CorpusEntry struct {
+	Password string
+	Count    int       // Number of distinct hashlists or imported files that cracked the password
+	LastSeen time.Time // Date of the latest run that cracked it
+}
+
+// Corpus is the cross-engagement found-passwords corpus. It holds plaintexts only, never hashes or
+// usernames, and is stored as tab-separated count, last-seen date and password, most frequent first.
+// Passwords that hashcat would hex-encode are stored as $HEX[...].
+type Corpus struct {
+	filename string
+	entries  map[string]*CorpusEntry
+	counted  map[string]struct{} // Fingerprints of the source and password pairs already counted
+}
+
+// corpusCountedSuffix names the file next to the corpus that lists which password was counted for which
+// source, as SHA-256 fingerprints, so that re-running a hashlist does not count its passwords again.
+const corpusCountedSuffix = ".counted"
+
+// LoadCorpus reads the corpus file. A missing file is an empty corpus.
+func LoadCorpus(filename string) (*Corpus, error) {
+	corpus := &Corpus{filename: filename, entries: make(map[string]*CorpusEntry), counted: make(map[string]struct{})}
+	counted, err := ReadLines(filename + corpusCountedSuffix)
+	if err != nil {
+		return nil, err
+	}
+	for _, fingerprint := range counted {
+		corpus.counted[fingerprint] = struct{}{}
+	}
+
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return corpus, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open corpus %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		fields := strings.SplitN(scanner.Text(), "\t", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid corpus line %d in %s", number, filename)
+		}
+		count, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid count on corpus line %d in %s: %w", number, filename, err)
+		}
+		lastSeen, err := time.Parse(corpusDate, fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid date on corpus line %d in %s: %w", number, filename, err)
+		}
+		password := DecodeHexPlain(fields[2])
+		corpus.entries[password] = &CorpusEntry{Password: password, Count: count, LastSeen: lastSeen}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan corpus %s: %w", filename, err)
+	}
+	return corpus, nil
+}
+
+// Add records the passwords cracked from one source, such as a hashlist identified by its SHA-256, and
+// returns how many were new to the corpus. Each password is counted at most once per source, however
+// often the source is run. Blank passwords are skipped.
+func (c *Corpus) Add(passwords []string, seen time.Time, source string) int {
+	added := 0
+	for _, password := range passwords {
+		if password == "" {
+			continue
+		}
+		fingerprint := corpusFingerprint(source, password)
+		if _, ok := c.counted[fingerprint]; ok {
+			if entry, ok := c.entries[password]; ok && seen.After(entry.LastSeen) {
+				entry.LastSeen = seen
+			}
+			continue
+		}
+		c.counted[fingerprint] = struct{}{}
+
+		entry, ok := c.entries[password]
+		if !ok {
+			entry = &CorpusEntry{Password: password}
+			c.entries[password] = entry
+			added++
+		}
+		entry.Count++
+		if seen.After(entry.LastSeen) {
+			entry.LastSeen = seen
+		}
+	}
+	return added
+}
+
+// corpusFingerprint identifies a password counted for a source without storing either.
+func corpusFingerprint(source, password string) string {
+	sum := sha256.Sum256([]byte(source + "\x00" + password))
+	return hex.EncodeToString(sum[:])
+}
+
+// Entries returns the corpus ranked by count, then by most recently seen, then by password.
+func (c *Corpus) Entries() []CorpusEntry {
+	entries := make([]CorpusEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		if !entries[i].LastSeen.Equal(entries[j].LastSeen) {
+			return entries[i].LastSeen.After(entries[j].LastSeen)
+		}
+		return entries[i].Password < entries[j].Password
+	})
+	return entries
+}
+
+// Top returns the n highest-ranked passwords.
+func (c *Corpus) Top(n int) []string {
+	entries := c.Entries()
+	if n < len(entries) {
+		entries = entries[:n]
+	}
+	passwords := make([]string, len(entries))
+	for i, entry := range entries {
+		passwords[i] = entry.Password
+	}
+	return passwords
+}
+
+// Save writes the corpus, replacing the file only once it is complete.
+func (c *Corpus) Save() error {
+	if dir := filepath.Dir(c.filename); dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return fmt.Errorf("failed to create corpus directory %s: %w", dir, err)
+		}
+	}
+	entries := c.Entries()
+	lines := make([]string, len(entries))
+	for i, entry := range entries {
+		lines[i] = fmt.Sprintf("%d\t%s\t%s", entry.Count, entry.LastSeen.Format(corpusDate), EncodeHexPlain(entry.Password))
+	}
+
+	fingerprints := make([]string, 0, len(c.counted))
+	for fingerprint := range c.counted {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Strings(fingerprints)
+
+	for filename, content := range map[string][]string{c.filename: lines, c.filename + corpusCountedSuffix: fingerprints} {
+		temp := filename + ".tmp"
+		if err := WriteToFile(temp, content); err != nil {
+			return err
+		}
+		if err := os.Rename(temp, filename); err != nil {
+			return fmt.Errorf("failed to replace corpus %s: %w", filename, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// crackLogStart prefixes the line recording when a run started, and crackLogStep the lines recording
+// when each step finished.
+const (
+	crackLogStart = "# started "
+	crackLogStep  = "# step "
+)
+
+// CrackLogEntry is one newly cracked `--show` line recorded by a pipeline step.
+type CrackLogEntry struct {
+	Time     time.Time
+	Step     string
+	Line     string
+	Password string
+}
+
+// StartCrackLog creates a crack log recording the run's start time.
+func StartCrackLog(filename string, start time.Time) error {
+	return WriteToFile(filename, []string{crackLogStart + start.Format(time.RFC3339)})
+}
+
+// CrackLogStep is a finished pipeline step recorded in a crack log, with how many lines it cracked.
+type CrackLogStep struct {
+	Time    time.Time
+	Name    string
+	Cracked int
+}
+
+// AppendCrackLog records that a step finished, followed by the lines it newly cracked as tab-separated
+// time, step and `--show` line.
+func AppendCrackLog(filename, step string, lines []string) error {
+	now := time.Now().Format(time.RFC3339)
+	entries := make([]string, 0, len(lines)+1)
+	entries = append(entries, fmt.Sprintf("%s%s %s %d", crackLogStep, now, step, len(lines)))
+	for _, line := range lines {
+		entries = append(entries, now+"\t"+step+"\t"+line)
+	}
+	return AppendToFile(filename, entries)
+}
+
+// ReadCrackLogSteps returns the finished steps recorded in a crack log, in order.
+func ReadCrackLogSteps(filename string) ([]CrackLogStep, error) {
+	lines, err := ReadLines(filename)
+	if err != nil {
+		return nil, err
+	}
+	var steps []CrackLogStep
+	for _, line := range lines {
+		fields := strings.Fields(strings.TrimPrefix(line, crackLogStep))
+		if !strings.HasPrefix(line, crackLogStep) || len(fields) != 3 {
+			continue
+		}
+		finished, err := time.Parse(time.RFC3339, fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid step time in %s: %w", filename, err)
+		}
+		cracked, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid step count in %s: %w", filename, err)
+		}
+		steps = append(steps, CrackLogStep{Time: finished, Name: fields[1], Cracked: cracked})
+	}
+	return steps, nil
+}
+
+// ReadCrackLog reads a crack log, returning the run's start time and its entries in order. $HEX[...]
+// passwords are decoded.
+func ReadCrackLog(filename string) (time.Time, []CrackLogEntry, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var start time.Time
+	var entries []CrackLogEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, crackLogStart) {
+			start, err = time.Parse(time.RFC3339, strings.TrimPrefix(line, crackLogStart))
+			if err != nil {
+				return time.Time{}, nil, fmt.Errorf("invalid start time in %s: %w", filename, err)
+			}
+			continue
+		}
+
+		fields := strings.SplitN(line, "\t", 3)
+		if strings.HasPrefix(line, crackLogStep) || len(fields) != 3 {
+			continue
+		}
+		crackedAt, err := time.Parse(time.RFC3339, fields[0])
+		if err != nil {
+			return time.Time{}, nil, fmt.Errorf("invalid time in %s: %w", filename, err)
+		}
+		parts := strings.Split(fields[2], ":")
+		entries = append(entries, CrackLogEntry{
+			Time:     crackedAt,
+			Step:     fields[1],
+			Line:     fields[2],
+			Password: DecodeHexPlain(parts[len(parts)-1]),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return time.Time{}, nil, fmt.Errorf("failed to scan file %s: %w", filename, err)
+	}
+	if start.IsZero() && len(entries) > 0 {
+		start = entries[0].Time
+	}
+	return start, entries, nil
+}
+
+// ReadLines returns the lines of a file, or no lines if the file does not exist.
+func ReadLines(filename string) ([]string, error) {
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan file %s: %w", filename, err)
+	}
+	return lines, nil
+}
@@ -8,35 +8,28 @@ import (
 	"unicode"
 )
 
-// ExtractUsernames extracts usernames from a hashlist file, handling DOMAIN\\username formats.
-func ExtractUsernames(hashlist string) ([]string, error) {
-	file, err := os.Open(hashlist)
+// ExtractAccounts parses the account field of every line in a hashlist file into a normalised Account.
+func ExtractAccounts(hashlist string) ([]Account, error) {
+	entries, err := ParseHashlist(hashlist)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open file %s: %w", hashlist, err)
+		return nil, err
 	}
-	defer file.Close()
 
-	var usernames []string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		parts := strings.Split(line, ":")
-		if len(parts) > 0 {
-			username := parts[0]
-			if strings.Contains(username, "\\") {
-				usernameParts := strings.Split(username, "\\")
-				if len(usernameParts) > 1 {
-					username = usernameParts[1]
-				}
-			}
-			usernames = append(usernames, username)
-		}
+	accounts := make([]Account, len(entries))
+	for i, entry := range entries {
+		accounts[i] = entry.Account
 	}
+	return accounts, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to scan file %s: %w", hashlist, err)
+// ExtractUsernames extracts usernames and their base words from a hashlist file, handling UPN and
+// DOMAIN\\username formats and skipping machine, built-in and history accounts.
+func ExtractUsernames(hashlist string) ([]string, error) {
+	accounts, err := ExtractAccounts(hashlist)
+	if err != nil {
+		return nil, err
 	}
-	return usernames, nil
+	return UsernameCandidates(accounts), nil
 }
 
 // ExtractPasswords parses a file and extracts unique, non-empty passwords from `user:password` lines.
@@ -121,3 +114,51 @@ func removeNonASCII(input string) string {
 	}
 	return builder.String()
 }
+
+// ReadCrackedPasswords returns the password from every `user:password` or `hash:password` line of a
+// cracked file, keeping duplicates so that reuse across accounts is counted.
+func ReadCrackedPasswords(filename string) ([]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan file %s: %w", filename, err)
+	}
+	return CrackedPasswords(lines), nil
+}
+
+// CrackedPasswords returns the decoded password from every `user:password` or `hash:password` line,
+// skipping blank passwords.
+func CrackedPasswords(lines []string) []string {
+	var passwords []string
+	for _, line := range lines {
+		parts := strings.Split(line, ":")
+		if len(parts) > 1 {
+			if password := parts[len(parts)-1]; password != "" {
+				passwords = append(passwords, DecodeHexPlain(password))
+			}
+		}
+	}
+	return passwords
+}
+
+// CountBlankPasswords counts the `user:` or `hash:` lines with a blank password, such as the
+// blank-password hashes found before cracking, which CrackedPasswords skips.
+func CountBlankPasswords(lines []string) int {
+	blank := 0
+	for _, line := range lines {
+		if strings.HasSuffix(line, ":") {
+			blank++
+		}
+	}
+	return blank
+}
@@ -2,8 +2,11 @@ package utils
 
 import (
 	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"os"
 	"strings"
 
@@ -72,28 +75,73 @@ func ValidateFiles(files []string) error {
 	return nil
 }
 
+// countBufferSize is the read buffer used when counting lines.
+const countBufferSize = 1 << 20
+
+// CountLines counts the lines of a file without loading it, including a last line with no newline.
+// An empty file has no lines.
 func CountLines(filename string) (int, error) {
-	content, err := ioutil.ReadFile(filename)
-	if err == nil {
-		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
-		return len(lines), nil
+	file, err := os.Open(filename)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	count := 0
+	lastByte := byte('\n')
+	buffer := make([]byte, countBufferSize)
+	for {
+		n, err := file.Read(buffer)
+		if n > 0 {
+			count += bytes.Count(buffer[:n], []byte{'\n'})
+			lastByte = buffer[n-1]
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read file %s: %w", filename, err)
+		}
 	}
-	return 0, nil
+	if lastByte != '\n' {
+		count++
+	}
+	return count, nil
 }
 
-func CompareCrackedFiles(currentFile, previousFile string) (int, error) {
-	currentCount, err := CountLines(currentFile)
+// CountRules counts the rules of a hashcat rule file, skipping blank lines and # comments as hashcat does.
+func CountRules(filename string) (int, error) {
+	file, err := os.Open(filename)
 	if err != nil {
-		return 0, err
+		return 0, fmt.Errorf("failed to open file %s: %w", filename, err)
 	}
+	defer file.Close()
 
-	previousCount := 0
-	if _, err := os.Stat(previousFile); err == nil { // Previous file exists
-		previousCount, err = CountLines(previousFile)
-		if err != nil {
-			return 0, err
+	count := 0
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) != "" && !strings.HasPrefix(line, "#") {
+			count++
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+	return count, nil
+}
 
-	return currentCount - previousCount, nil
+// FileSHA256 returns the hex SHA-256 of a file's content.
+func FileSHA256(filename string) (string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to read file %s: %w", filename, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
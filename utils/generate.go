@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// OrgContext describes the organisation being assessed, used to generate context-specific candidates.
+type OrgContext struct {
+	Company   string
+	City      string
+	Keywords  []string
+	StartYear int
+	EndYear   int
+	Languages []string
+}
+
+// Empty reports whether no company, city or keywords were given.
+func (c OrgContext) Empty() bool {
+	return c.Company == "" && c.City == "" && len(c.Keywords) == 0
+}
+
+// contextWords holds the seasons, months and common password words for one language.
+type contextWords struct {
+	Seasons []string
+	Months  []string
+	Common  []string
+}
+
+// contextLanguages maps language codes to their seasonal and common password words.
+var contextLanguages = map[string]contextWords{
+	"en": {
+		Seasons: []string{"spring", "summer", "autumn", "fall", "winter"},
+		Months:  []string{"january", "february", "march", "april", "may", "june", "july", "august", "september", "october", "november", "december"},
+		Common:  []string{"welcome", "password", "changeme", "letmein"},
+	},
+	"af": {
+		Seasons: []string{"lente", "somer", "herfs", "winter"},
+		Months:  []string{"januarie", "februarie", "maart", "april", "mei", "junie", "julie", "augustus", "september", "oktober", "november", "desember"},
+		Common:  []string{"welkom", "wagwoord"},
+	},
+	"nl": {
+		Seasons: []string{"lente", "zomer", "herfst", "winter"},
+		Months:  []string{"januari", "februari", "maart", "april", "mei", "juni", "juli", "augustus", "september", "oktober", "november", "december"},
+		Common:  []string{"welkom", "wachtwoord"},
+	},
+	"de": {
+		Seasons: []string{"fruehling", "sommer", "herbst", "winter"},
+		Months:  []string{"januar", "februar", "maerz", "april", "mai", "juni", "juli", "august", "september", "oktober", "november", "dezember"},
+		Common:  []string{"willkommen", "passwort"},
+	},
+	"fr": {
+		Seasons: []string{"printemps", "ete", "automne", "hiver"},
+		Months:  []string{"janvier", "fevrier", "mars", "avril", "mai", "juin", "juillet", "aout", "septembre", "octobre", "novembre", "decembre"},
+		Common:  []string{"bienvenue", "motdepasse"},
+	},
+	"es": {
+		Seasons: []string{"primavera", "verano", "otono", "invierno"},
+		Months:  []string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		Common:  []string{"bienvenido", "contrasena"},
+	},
+}
+
+// contextSeparators are placed between a base word and a year.
+var contextSeparators = []string{"", "@", "_", ".", "-", "#"}
+
+// contextSuffixes are appended to every generated candidate.
+var contextSuffixes = []string{"", "!", "1", "1!", "123", "01", "@", "#", "!!"}
+
+// ContextLanguages returns the supported language codes for context wordlists.
+func ContextLanguages() []string {
+	languages := make([]string, 0, len(contextLanguages))
+	for language := range contextLanguages {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Year ranges are limited to four-digit years and a bounded span, which keeps wordlists a sensible size.
+const (
+	minYear     = 1000
+	maxYear     = 9999
+	maxYearSpan = 50
+)
+
+// ParseYearRange parses a year range such as "2020-2025" or a single year such as "2024".
+func ParseYearRange(value string) (int, int, error) {
+	startText, endText, found := strings.Cut(value, "-")
+	if !found {
+		endText = startText
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(startText))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid start year in %q: %w", value, err)
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(endText))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid end year in %q: %w", value, err)
+	}
+	if start < minYear || end > maxYear {
+		return 0, 0, fmt.Errorf("invalid year range %q: use four-digit years", value)
+	}
+	if start > end {
+		return 0, 0, fmt.Errorf("start year %d is after end year %d", start, end)
+	}
+	if end-start >= maxYearSpan {
+		return 0, 0, fmt.Errorf("year range %q spans more than %d years", value, maxYearSpan)
+	}
+	return start, end, nil
+}
+
+// GenerateContextWordlist combines the company, city and keywords with seasons, months and common words in
+// the configured languages, then with years, separators and suffixes, and returns the deduplicated candidates.
+func GenerateContextWordlist(ctx OrgContext) ([]string, error) {
+	var bases []string
+	for _, word := range append([]string{ctx.Company, ctx.City}, ctx.Keywords...) {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		bases = append(bases, word)
+		if squashed := strings.Join(strings.Fields(word), ""); squashed != word {
+			bases = append(bases, squashed)
+		}
+	}
+	if ctx.Company != "" && ctx.City != "" {
+		bases = append(bases, strings.Join(strings.Fields(ctx.Company+ctx.City), ""))
+	}
+
+	for _, language := range ctx.Languages {
+		words, ok := contextLanguages[strings.ToLower(strings.TrimSpace(language))]
+		if !ok {
+			return nil, fmt.Errorf("unsupported language %q (supported: %s)", language, strings.Join(ContextLanguages(), ", "))
+		}
+		bases = append(bases, words.Seasons...)
+		bases = append(bases, words.Months...)
+		bases = append(bases, words.Common...)
+	}
+
+	years := []string{""}
+	for year := ctx.StartYear; year <= ctx.EndYear; year++ {
+		full := strconv.Itoa(year)
+		years = append(years, full, full[len(full)-2:])
+	}
+
+	seen := make(map[string]struct{})
+	var candidates []string
+	for _, base := range bases {
+		for _, word := range casings(base) {
+			for _, year := range years {
+				separators := contextSeparators
+				if year == "" {
+					separators = []string{""}
+				}
+				for _, separator := range separators {
+					for _, suffix := range contextSuffixes {
+						candidate := word + separator + year + suffix
+						if _, ok := seen[candidate]; ok {
+							continue
+						}
+						seen[candidate] = struct{}{}
+						candidates = append(candidates, candidate)
+					}
+				}
+			}
+		}
+	}
+	return candidates, nil
+}
+
+// casings returns the word as given plus its lowercase, capitalised and uppercase forms, without duplicates.
+func casings(word string) []string {
+	lower := strings.ToLower(word)
+	runes := []rune(lower)
+	runes[0] = unicode.ToUpper(runes[0])
+
+	var forms []string
+	for _, form := range []string{word, lower, string(runes), strings.ToUpper(word)} {
+		if !contains(forms, form) {
+			forms = append(forms, form)
+		}
+	}
+	return forms
+}
+
+// contains reports whether list contains value.
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// SeasonWords returns the lowercase season names of every supported language.
+func SeasonWords() []string {
+	var seasons []string
+	for _, language := range ContextLanguages() {
+		for _, season := range contextLanguages[language].Seasons {
+			if !contains(seasons, season) {
+				seasons = append(seasons, season)
+			}
+		}
+	}
+	return seasons
+}
@@ -0,0 +1,281 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DefaultSensitiveGroups are always treated as privileged.
+var DefaultSensitiveGroups = []string{"Domain Admins", "Enterprise Admins"}
+
+// GroupMemberships maps lowercase usernames to the names of the groups they belong to.
+type GroupMemberships map[string][]string
+
+// add records that username is a member of group, ignoring duplicates.
+func (m GroupMemberships) add(username, group string) {
+	username = strings.ToLower(NormalizeUsername(username).Username)
+	group = strings.TrimSpace(group)
+	if username == "" || group == "" {
+		return
+	}
+	for _, existing := range m[username] {
+		if strings.EqualFold(existing, group) {
+			return
+		}
+	}
+	m[username] = append(m[username], group)
+}
+
+// Groups returns the groups an account belongs to.
+func (m GroupMemberships) Groups(account Account) []string {
+	return m[strings.ToLower(account.Username)]
+}
+
+// SensitiveGroups returns the groups of an account that appear in sensitive, compared case-insensitively.
+func (m GroupMemberships) SensitiveGroups(account Account, sensitive []string) []string {
+	var matched []string
+	for _, group := range m.Groups(account) {
+		for _, name := range sensitive {
+			if strings.EqualFold(group, strings.TrimSpace(name)) {
+				matched = append(matched, group)
+				break
+			}
+		}
+	}
+	sort.Strings(matched)
+	return matched
+}
+
+// LoadGroupMemberships reads group membership exports: ldapdomaindump domain_users.json and
+// domain_groups.json, BloodHound users and groups JSON, or CSV lines of group,member (or group:member).
+// BloodHound group members are SIDs, so the matching users export must be loaded in the same call.
+// Nested groups from domain_groups.json or BloodHound are expanded, so members of a nested group also
+// belong to every group containing it.
+func LoadGroupMemberships(files []string) (GroupMemberships, error) {
+	memberships := make(GroupMemberships)
+	groupParents := make(map[string][]string) // Lowercase ldapdomaindump group name to the groups it is a member of
+	groupNames := make(map[string]string)     // Lowercase ldapdomaindump group name to the name as written
+	sidNames := make(map[string]string)
+	var bloodhoundGroups []bloodhoundObject
+
+	for _, filename := range files {
+		content, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", filename, err)
+		}
+		trimmed := bytes.TrimSpace(content)
+
+		switch {
+		case bytes.HasPrefix(trimmed, []byte("[")):
+			if err := loadLdapDomainDump(trimmed, memberships, groupParents, groupNames); err != nil {
+				return nil, fmt.Errorf("failed to parse ldapdomaindump file %s: %w", filename, err)
+			}
+		case bytes.HasPrefix(trimmed, []byte("{")):
+			var export bloodhoundExport
+			if err := json.Unmarshal(trimmed, &export); err != nil {
+				return nil, fmt.Errorf("failed to parse BloodHound file %s: %w", filename, err)
+			}
+			for _, object := range export.Data {
+				switch export.Meta.Type {
+				case "users":
+					sidNames[object.ObjectIdentifier] = object.samAccountName()
+				case "groups":
+					bloodhoundGroups = append(bloodhoundGroups, object)
+				}
+			}
+		default:
+			loadGroupCSV(string(trimmed), memberships)
+		}
+	}
+
+	// A user in a group that is itself a member of Domain Admins counts as a member of Domain Admins
+	for username, groups := range memberships {
+		for _, group := range groups {
+			for _, parent := range reachable(strings.ToLower(group), groupParents) {
+				memberships.add(username, groupNames[parent])
+			}
+		}
+	}
+
+	groupMembers := make(map[string][]string, len(bloodhoundGroups))
+	for _, group := range bloodhoundGroups {
+		for _, member := range group.Members {
+			groupMembers[group.ObjectIdentifier] = append(groupMembers[group.ObjectIdentifier], member.ObjectIdentifier)
+		}
+	}
+	for _, group := range bloodhoundGroups {
+		for _, sid := range reachable(group.ObjectIdentifier, groupMembers) {
+			if name, ok := sidNames[sid]; ok {
+				memberships.add(name, group.groupName())
+			}
+		}
+	}
+	return memberships, nil
+}
+
+// reachable returns every node reachable from start through edges, not including start itself. It is
+// the closure used to expand nested groups, following members down or parent groups up; cycles are safe.
+func reachable(start string, edges map[string][]string) []string {
+	visited := map[string]bool{start: true}
+	var found []string
+	pending := []string{start}
+	for len(pending) > 0 {
+		node := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, next := range edges[node] {
+			if !visited[next] {
+				visited[next] = true
+				found = append(found, next)
+				pending = append(pending, next)
+			}
+		}
+	}
+	return found
+}
+
+// ldapDumpObject is an entry of an ldapdomaindump JSON file.
+type ldapDumpObject struct {
+	Attributes map[string]json.RawMessage `json:"attributes"`
+}
+
+// stringValues returns the string values of a multi-valued ldapdomaindump attribute.
+func (o ldapDumpObject) stringValues(name string) []string {
+	raw, ok := o.Attributes[name]
+	if !ok {
+		return nil
+	}
+	var values []string
+	if err := json.Unmarshal(raw, &values); err == nil {
+		return values
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return []string{value}
+	}
+	return nil
+}
+
+// loadLdapDomainDump reads the memberOf attribute of an ldapdomaindump users or groups file. Users are
+// recorded in memberships and groups, which are nested in other groups, in groupParents by lowercase
+// name, with the names as written in groupNames.
+func loadLdapDomainDump(content []byte, memberships GroupMemberships, groupParents map[string][]string, groupNames map[string]string) error {
+	var objects []ldapDumpObject
+	if err := json.Unmarshal(content, &objects); err != nil {
+		return err
+	}
+	for _, object := range objects {
+		if object.isGroup() {
+			name := object.groupName()
+			for _, dn := range object.stringValues("memberOf") {
+				parent := CommonName(dn)
+				groupNames[strings.ToLower(parent)] = parent
+				groupParents[name] = append(groupParents[name], strings.ToLower(parent))
+			}
+			continue
+		}
+		names := object.stringValues("sAMAccountName")
+		if len(names) == 0 {
+			continue
+		}
+		for _, dn := range object.stringValues("memberOf") {
+			memberships.add(names[0], CommonName(dn))
+		}
+	}
+	return nil
+}
+
+// isGroup reports whether an ldapdomaindump entry is a group, as in domain_groups.json.
+func (o ldapDumpObject) isGroup() bool {
+	for _, class := range o.stringValues("objectClass") {
+		if strings.EqualFold(class, "group") {
+			return true
+		}
+	}
+	return false
+}
+
+// groupName returns the lowercase CN of an ldapdomaindump group, as its memberOf references name it.
+func (o ldapDumpObject) groupName() string {
+	if names := o.stringValues("cn"); len(names) > 0 {
+		return strings.ToLower(names[0])
+	}
+	if dns := o.stringValues("distinguishedName"); len(dns) > 0 {
+		return strings.ToLower(CommonName(dns[0]))
+	}
+	if names := o.stringValues("sAMAccountName"); len(names) > 0 {
+		return strings.ToLower(names[0])
+	}
+	return ""
+}
+
+// CommonName returns the first CN of a distinguished name, or the value unchanged if it is not a DN.
+func CommonName(dn string) string {
+	first, _, _ := strings.Cut(dn, ",")
+	first = strings.TrimSpace(first)
+	if len(first) > 3 && strings.EqualFold(first[:3], "CN=") {
+		return first[3:]
+	}
+	return dn
+}
+
+// bloodhoundExport is a SharpHound users or groups JSON file.
+type bloodhoundExport struct {
+	Data []bloodhoundObject `json:"data"`
+	Meta struct {
+		Type string `json:"type"`
+	} `json:"meta"`
+}
+
+// bloodhoundObject is a user or group in a SharpHound export.
+type bloodhoundObject struct {
+	ObjectIdentifier string             `json:"ObjectIdentifier"`
+	Properties       map[string]any     `json:"Properties"`
+	Members          []bloodhoundMember `json:"Members"`
+}
+
+// bloodhoundMember is a member reference of a SharpHound group.
+type bloodhoundMember struct {
+	ObjectIdentifier string `json:"ObjectIdentifier"`
+	ObjectType       string `json:"ObjectType"`
+}
+
+// property returns a string property of a SharpHound object.
+func (o bloodhoundObject) property(name string) string {
+	value, _ := o.Properties[name].(string)
+	return value
+}
+
+// samAccountName returns the user's sAMAccountName, falling back to the part of its name before the @.
+func (o bloodhoundObject) samAccountName() string {
+	if name := o.property("samaccountname"); name != "" {
+		return name
+	}
+	name, _, _ := strings.Cut(o.property("name"), "@")
+	return name
+}
+
+// groupName returns the group's name without the @DOMAIN suffix.
+func (o bloodhoundObject) groupName() string {
+	name, _, _ := strings.Cut(o.property("name"), "@")
+	return name
+}
+
+// loadGroupCSV reads group,member or group:member lines, skipping a group,member header.
+func loadGroupCSV(content string, memberships GroupMemberships) {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		separator := ","
+		if !strings.Contains(line, ",") {
+			separator = ":"
+		}
+		group, member, found := strings.Cut(line, separator)
+		if !found || strings.EqualFold(strings.TrimSpace(group), "group") {
+			continue
+		}
+		memberships.add(strings.Trim(strings.TrimSpace(member), `"`), strings.Trim(strings.TrimSpace(group), `"`))
+	}
+}
@@ -0,0 +1,130 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// HashlistEntry is one account line of a hashlist.
+type HashlistEntry struct {
+	Account Account
+	Hash    string        // Hash to crack; the NT hash for secretsdump lines
+	LMHash  string        // LM hash from secretsdump lines, empty otherwise
+	Line    int           // 1-based line number in the hashlist
+	AD      *ADAttributes // Active Directory attributes, when loaded with AttachADAttributes
+}
+
+var hex32 = regexp.MustCompile(`^[0-9a-fA-F]{32}$`)
+
+// ParseHashlist reads a `user:hash` or secretsdump `user:rid:lmhash:nthash:::` hashlist.
+func ParseHashlist(filename string) ([]HashlistEntry, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var entries []HashlistEntry
+	scanner := bufio.NewScanner(file)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		entries = append(entries, ParseHashlistLine(line, lineNumber))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan file %s: %w", filename, err)
+	}
+	return entries, nil
+}
+
+// ParseHashlistLine parses a single hashlist line.
+func ParseHashlistLine(line string, lineNumber int) HashlistEntry {
+	parts := strings.Split(line, ":")
+	entry := HashlistEntry{Account: NormalizeUsername(parts[0]), Line: lineNumber}
+
+	if len(parts) >= 4 && hex32.MatchString(parts[2]) && hex32.MatchString(parts[3]) {
+		entry.LMHash = NormalizeHash(parts[2])
+		entry.Hash = NormalizeHash(parts[3])
+	} else if len(parts) > 1 {
+		entry.Hash = NormalizeHash(strings.Join(parts[1:], ":"))
+	}
+	return entry
+}
+
+// NormalizeHash lowercases hex-only hashes so that identical hashes compare equal; other formats are kept as-is.
+func NormalizeHash(hash string) string {
+	hash = strings.TrimSpace(hash)
+	for _, r := range hash {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return hash
+		}
+	}
+	return strings.ToLower(hash)
+}
+
+// MatchCracked maps the hashes of entries to passwords found in cracked lines, which may be
+// `hash:password` potfile lines or `user:hash:password` `--show` lines. $HEX[...] passwords are decoded.
+func MatchCracked(entries []HashlistEntry, crackedLines []string) map[string]string {
+	hashes := hashSet(entries)
+	cracked := make(map[string]string)
+	for _, line := range crackedLines {
+		if hash, password, ok := matchCrackedLine(line, hashes); ok {
+			cracked[hash] = password
+		}
+	}
+	return cracked
+}
+
+// FilterCracked keeps the cracked lines whose hash belongs to one of entries.
+func FilterCracked(entries []HashlistEntry, crackedLines []string) []string {
+	hashes := hashSet(entries)
+	var kept []string
+	for _, line := range crackedLines {
+		if _, _, ok := matchCrackedLine(line, hashes); ok {
+			kept = append(kept, line)
+		}
+	}
+	return kept
+}
+
+// hashSet returns the distinct hashes of entries.
+func hashSet(entries []HashlistEntry) map[string]struct{} {
+	hashes := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		if entry.Hash != "" {
+			hashes[entry.Hash] = struct{}{}
+		}
+	}
+	return hashes
+}
+
+// matchCrackedLine splits a cracked line into one of the known hashes and its password.
+func matchCrackedLine(line string, hashes map[string]struct{}) (string, string, bool) {
+	separator := strings.LastIndex(line, ":")
+	if separator < 0 {
+		return "", "", false
+	}
+	prefix, password := line[:separator], line[separator+1:]
+
+	// Try the whole prefix as the hash, then drop leading fields such as the username
+	for {
+		if hash := NormalizeHash(prefix); hash != "" {
+			if _, ok := hashes[hash]; ok {
+				return hash, DecodeHexPlain(password), true
+			}
+		}
+		next := strings.Index(prefix, ":")
+		if next < 0 {
+			return "", "", false
+		}
+		prefix = prefix[next+1:]
+	}
+}
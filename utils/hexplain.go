@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+)
+
+// hexPrefix and hexSuffix wrap plaintexts that hashcat writes in hex form.
+const (
+	hexPrefix = "$HEX["
+	hexSuffix = "]"
+)
+
+// EncodeHexPlain returns a plaintext as hashcat writes it: as-is, or as $HEX[...] when it is not valid
+// UTF-8, contains control bytes or a colon, or would itself look like a $HEX[...] value.
+func EncodeHexPlain(plain string) string {
+	needsHex := strings.HasPrefix(plain, hexPrefix) || !utf8.ValidString(plain)
+	for i := 0; i < len(plain) && !needsHex; i++ {
+		if plain[i] < 0x20 || plain[i] == 0x7f || plain[i] == ':' {
+			needsHex = true
+		}
+	}
+	if needsHex {
+		return hexPrefix + hex.EncodeToString([]byte(plain)) + hexSuffix
+	}
+	return plain
+}
+
+// DecodeHexPlain reverses EncodeHexPlain, leaving plaintexts that are not valid $HEX[...] values unchanged.
+func DecodeHexPlain(plain string) string {
+	if strings.HasPrefix(plain, hexPrefix) && strings.HasSuffix(plain, hexSuffix) {
+		if decoded, err := hex.DecodeString(plain[len(hexPrefix) : len(plain)-len(hexSuffix)]); err == nil {
+			return string(decoded)
+		}
+	}
+	return plain
+}
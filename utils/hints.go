@@ -0,0 +1,173 @@
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// usernameColumns are the header names accepted for the column that keys a hint CSV.
+var usernameColumns = map[string]struct{}{
+	"username":       {},
+	"user":           {},
+	"samaccountname": {},
+	"account":        {},
+}
+
+// yearPattern matches a whole run of digits that is a year, so that IDs and phone numbers containing
+// 19xx or 20xx are not taken for years.
+var yearPattern = regexp.MustCompile(`^(?:19|20)\d\d$`)
+
+// LoadAccountHints reads a CSV file with a header row and a username column (username, user,
+// sAMAccountName or account) and expands every other column into targeted candidate words.
+// The result maps lowercase usernames to their hint words.
+func LoadAccountHints(filename string) (map[string][]string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header of %s: %w", filename, err)
+	}
+	userColumn := -1
+	for i, name := range header {
+		if _, ok := usernameColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			userColumn = i
+			break
+		}
+	}
+	if userColumn < 0 {
+		return nil, fmt.Errorf("no username column found in %s", filename)
+	}
+
+	hints := make(map[string][]string)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", filename, err)
+		}
+		if userColumn >= len(record) {
+			continue
+		}
+		username := strings.ToLower(NormalizeUsername(record[userColumn]).Username)
+		if username == "" {
+			continue
+		}
+
+		var values []string
+		for i, value := range record {
+			if i != userColumn {
+				values = append(values, value)
+			}
+		}
+		hints[username] = append(hints[username], ExpandHintValues(values)...)
+	}
+	return hints, nil
+}
+
+// ExpandHintValues turns the raw details known about one account into deduplicated candidate words:
+// each word and number of three or more digits on its own, multi-word values joined together and as
+// initial+surname, and every alphabetic word combined with any years found (full and two-digit).
+func ExpandHintValues(values []string) []string {
+	var words, numbers, years []string
+	var candidates []string
+	seen := make(map[string]struct{})
+	add := func(candidate string) {
+		if candidate == "" {
+			return
+		}
+		if _, ok := seen[candidate]; ok {
+			return
+		}
+		seen[candidate] = struct{}{}
+		candidates = append(candidates, candidate)
+	}
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		for _, run := range digitRun.FindAllString(value, -1) {
+			if yearPattern.MatchString(run) {
+				years = append(years, run)
+			}
+		}
+
+		fields := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		var valueWords []string
+		for _, field := range fields {
+			if isDigits(field) {
+				if len(field) >= 3 { // Skip day and month fragments of dates
+					numbers = append(numbers, field)
+				}
+			} else {
+				valueWords = append(valueWords, field)
+			}
+		}
+		words = append(words, valueWords...)
+		if len(valueWords) > 1 {
+			add(strings.Join(valueWords, ""))
+			add(string([]rune(valueWords[0])[0]) + valueWords[len(valueWords)-1])
+		}
+	}
+
+	for _, word := range words {
+		add(word)
+	}
+	for _, number := range numbers {
+		add(number)
+	}
+	for _, word := range words {
+		if !isLetters(word) {
+			continue
+		}
+		for _, year := range years {
+			add(word + year)
+			add(word + year[2:])
+		}
+	}
+	return candidates
+}
+
+// isLetters reports whether s is non-empty and consists only of letters.
+func isLetters(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDigits reports whether s is non-empty and consists only of digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,245 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// PasswordHistory links an account's secretsdump `_historyN` entries to its current hash.
+type PasswordHistory struct {
+	Account Account
+	Current *HashlistEntry  // Current hash, nil if the hashlist only holds history entries for the account
+	History []HashlistEntry // History entries, most recent (`_history0`) first
+}
+
+// LinkPasswordHistory groups history entries with the account they belong to. Accounts without
+// history entries are left out; the result is ordered by account name.
+func LinkPasswordHistory(entries []HashlistEntry) []PasswordHistory {
+	byAccount := make(map[string]*PasswordHistory)
+	var keys []string
+	get := func(account Account) *PasswordHistory {
+		key := strings.ToLower(account.Domain + "\\" + account.Username)
+		history, ok := byAccount[key]
+		if !ok {
+			history = &PasswordHistory{Account: account}
+			byAccount[key] = history
+			keys = append(keys, key)
+		}
+		return history
+	}
+
+	for i := range entries {
+		entry := entries[i]
+		switch entry.Account.Kind {
+		case AccountHistory:
+			history := get(entry.Account)
+			history.History = append(history.History, entry)
+		case AccountUser, AccountMachine, AccountBuiltin:
+			history := get(entry.Account)
+			history.Account = entry.Account
+			history.Current = &entries[i]
+		}
+	}
+
+	var histories []PasswordHistory
+	sort.Strings(keys)
+	for _, key := range keys {
+		history := byAccount[key]
+		if len(history.History) == 0 {
+			continue
+		}
+		sort.Slice(history.History, func(i, j int) bool {
+			return history.History[i].Account.HistoryIndex < history.History[j].Account.HistoryIndex
+		})
+		histories = append(histories, *history)
+	}
+	return histories
+}
+
+// Chain returns the account's hashes from oldest to current. secretsdump's `_history0` usually repeats
+// the current hash, so consecutive entries with the same hash are kept once.
+func (h PasswordHistory) Chain() []HashlistEntry {
+	var chain []HashlistEntry
+	for i := len(h.History) - 1; i >= 0; i-- {
+		chain = append(chain, h.History[i])
+	}
+	if h.Current != nil {
+		chain = append(chain, *h.Current)
+	}
+
+	var deduplicated []HashlistEntry
+	for _, entry := range chain {
+		if n := len(deduplicated); n > 0 && deduplicated[n-1].Hash == entry.Hash {
+			deduplicated[n-1] = entry // Keep the newer entry
+			continue
+		}
+		deduplicated = append(deduplicated, entry)
+	}
+	return deduplicated
+}
+
+// HistoryCandidates predicts current passwords for accounts whose current hash is not cracked yet,
+// from the most recent cracked password in their history.
+func HistoryCandidates(histories []PasswordHistory, cracked map[string]string) []string {
+	seen := make(map[string]struct{})
+	var candidates []string
+	for _, history := range histories {
+		if history.Current != nil {
+			if _, ok := cracked[history.Current.Hash]; ok {
+				continue
+			}
+		}
+		chain := history.Chain()
+		for i := len(chain) - 1; i >= 0; i-- {
+			password, ok := cracked[chain[i].Hash]
+			if !ok {
+				continue
+			}
+			for _, candidate := range NextPasswordCandidates(password) {
+				if _, duplicate := seen[candidate]; !duplicate {
+					seen[candidate] = struct{}{}
+					candidates = append(candidates, candidate)
+				}
+			}
+			break
+		}
+	}
+	return candidates
+}
+
+var digitRun = regexp.MustCompile(`\d+`)
+
+// historyIncrements is how far ahead digit runs are stepped when predicting the next password,
+// covering accounts whose newer history entries were not cracked.
+const historyIncrements = 3
+
+// NextPasswordCandidates returns likely successors of a password: each digit run incremented
+// (keeping its width), the next seasons and months, and both combined with an incremented year.
+func NextPasswordCandidates(password string) []string {
+	seen := map[string]struct{}{password: {}}
+	var candidates []string
+	add := func(candidate string) {
+		if _, ok := seen[candidate]; !ok {
+			seen[candidate] = struct{}{}
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	for _, loc := range digitRun.FindAllStringIndex(password, -1) {
+		for step := 1; step <= historyIncrements; step++ {
+			add(password[:loc[0]] + incrementDigits(password[loc[0]:loc[1]], step) + password[loc[1]:])
+		}
+	}
+
+	for _, rotated := range rotateCycles(password) {
+		add(rotated.password)
+		if rotated.wrapped {
+			// Winter to spring or December to January usually comes with a new year
+			for _, loc := range digitRun.FindAllStringIndex(rotated.password, -1) {
+				add(rotated.password[:loc[0]] + incrementDigits(rotated.password[loc[0]:loc[1]], 1) + rotated.password[loc[1]:])
+			}
+		}
+	}
+	return candidates
+}
+
+// incrementDigits adds step to a run of digits, keeping leading zeros.
+func incrementDigits(digits string, step int) string {
+	value, err := strconv.ParseUint(digits, 10, 64)
+	if err != nil {
+		return digits
+	}
+	next := strconv.FormatUint(value+uint64(step), 10)
+	if len(next) < len(digits) {
+		next = strings.Repeat("0", len(digits)-len(next)) + next
+	}
+	return next
+}
+
+// rotation is a password with its season or month advanced by one.
+type rotation struct {
+	password string
+	wrapped  bool // The cycle wrapped around, e.g. winter to spring
+}
+
+var letterRun = regexp.MustCompile(`\pL+`)
+
+// rotateCycles advances each run of letters that is a season or month to the next one in its language.
+// Words shared by several languages, such as "winter", follow the first cycle that contains them.
+func rotateCycles(password string) []rotation {
+	var rotations []rotation
+	for _, loc := range letterRun.FindAllStringIndex(password, -1) {
+		original := password[loc[0]:loc[1]]
+	cycles:
+		for _, cycle := range RotationCycles() {
+			for i, word := range cycle {
+				if cycleMatch(original, word) {
+					next := matchCase(cycle[(i+1)%len(cycle)], original)
+					rotations = append(rotations, rotation{password: password[:loc[0]] + next + password[loc[1]:], wrapped: i == len(cycle)-1})
+					break cycles
+				}
+			}
+		}
+	}
+	return rotations
+}
+
+// CycleWord returns the season or month a password is built on, lowercase, or "" if it has none.
+func CycleWord(password string) string {
+	for _, run := range letterRun.FindAllString(password, -1) {
+		for _, cycle := range RotationCycles() {
+			for _, word := range cycle {
+				if cycleMatch(run, word) {
+					return word
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// cycleMatch reports whether a run of letters is the season or month word, counting "fall" as autumn.
+func cycleMatch(run, word string) bool {
+	return strings.EqualFold(run, word) || (word == "autumn" && strings.EqualFold(run, "fall"))
+}
+
+// RotationCycles returns the ordered seasons and months of each supported language, lowercase, English first.
+// English "fall" is left out in favour of "autumn".
+func RotationCycles() [][]string {
+	languages := []string{"en"}
+	for _, language := range ContextLanguages() {
+		if language != "en" {
+			languages = append(languages, language)
+		}
+	}
+
+	var cycles [][]string
+	for _, language := range languages {
+		words := contextLanguages[language]
+		var seasons []string
+		for _, season := range words.Seasons {
+			if season != "fall" {
+				seasons = append(seasons, season)
+			}
+		}
+		cycles = append(cycles, seasons, words.Months)
+	}
+	return cycles
+}
+
+// matchCase applies the casing of original (lower, upper or capitalised) to word.
+func matchCase(word, original string) string {
+	switch {
+	case original == strings.ToUpper(original):
+		return strings.ToUpper(word)
+	case unicode.IsUpper([]rune(original)[0]):
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		return string(runes)
+	default:
+		return word
+	}
+}
@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lineCount is a cached line count, valid while the file keeps the same size and modification time.
+type lineCount struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	Lines   int       `json:"lines"`
+}
+
+// CountLinesCached counts the lines of a file, reusing the count stored in an index file while the
+// file's size and modification time are unchanged. The index is keyed by absolute path and updated
+// when a file is counted. A damaged index is ignored and rebuilt.
+func CountLinesCached(filename, indexFile string) (int, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat file %s: %w", filename, err)
+	}
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve path %s: %w", filename, err)
+	}
+
+	index := make(map[string]lineCount)
+	if data, err := os.ReadFile(indexFile); err == nil {
+		if json.Unmarshal(data, &index) != nil {
+			index = make(map[string]lineCount)
+		}
+	} else if !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to read line count index %s: %w", indexFile, err)
+	}
+	if cached, ok := index[path]; ok && cached.Size == info.Size() && cached.ModTime.Equal(info.ModTime()) {
+		return cached.Lines, nil
+	}
+
+	lines, err := CountLines(filename)
+	if err != nil {
+		return 0, err
+	}
+	index[path] = lineCount{Size: info.Size(), ModTime: info.ModTime(), Lines: lines}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode line count index: %w", err)
+	}
+	temp := indexFile + ".tmp"
+	if err := os.WriteFile(temp, append(data, '\n'), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write line count index %s: %w", indexFile, err)
+	}
+	if err := os.Rename(temp, indexFile); err != nil {
+		return 0, fmt.Errorf("failed to replace line count index %s: %w", indexFile, err)
+	}
+	return lines, nil
+}
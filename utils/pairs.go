@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultAdminConventions are the naming conventions used to link admin accounts to their owner's
+// standard account, where {user} stands for the standard username.
+var DefaultAdminConventions = []string{
+	"{user}_adm", "{user}-adm", "{user}.adm", "adm_{user}", "adm-{user}", "adm.{user}",
+	"{user}_admin", "{user}-admin", "{user}.admin", "admin_{user}", "admin-{user}", "admin.{user}",
+	"a-{user}", "a_{user}", "{user}-a", "{user}_da", "da-{user}", "da_{user}",
+}
+
+// AccountPair links an admin account to the standard account of the same person.
+type AccountPair struct {
+	Standard   HashlistEntry
+	Admin      HashlistEntry
+	Convention string
+}
+
+// adminConvention is a compiled naming convention.
+type adminConvention struct {
+	pattern string
+	regex   *regexp.Regexp
+}
+
+// compileAdminConventions turns {user} conventions into case-insensitive regular expressions.
+func compileAdminConventions(conventions []string) ([]adminConvention, error) {
+	compiled := make([]adminConvention, 0, len(conventions))
+	for _, convention := range conventions {
+		prefix, suffix, found := strings.Cut(convention, "{user}")
+		if !found {
+			return nil, fmt.Errorf("admin naming convention %q has no {user} placeholder", convention)
+		}
+		regex := regexp.MustCompile("(?i)^" + regexp.QuoteMeta(prefix) + "(.+)" + regexp.QuoteMeta(suffix) + "$")
+		compiled = append(compiled, adminConvention{pattern: convention, regex: regex})
+	}
+	return compiled, nil
+}
+
+// FindAdminPairs links admin accounts to standard accounts in the same domain using the naming conventions.
+func FindAdminPairs(entries []HashlistEntry, conventions []string) ([]AccountPair, error) {
+	compiled, err := compileAdminConventions(conventions)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]HashlistEntry)
+	for _, entry := range entries {
+		if entry.Account.Kind == AccountUser {
+			byName[pairKey(entry.Account.Domain, entry.Account.Username)] = entry
+		}
+	}
+
+	var pairs []AccountPair
+	for _, admin := range entries {
+		if admin.Account.Kind != AccountUser {
+			continue
+		}
+		for _, convention := range compiled {
+			match := convention.regex.FindStringSubmatch(admin.Account.Username)
+			if match == nil {
+				continue
+			}
+			if standard, ok := byName[pairKey(admin.Account.Domain, match[1])]; ok && standard.Line != admin.Line {
+				pairs = append(pairs, AccountPair{Standard: standard, Admin: admin, Convention: convention.pattern})
+				break
+			}
+		}
+	}
+	return pairs, nil
+}
+
+// pairKey builds a case-insensitive lookup key for an account in a domain.
+func pairKey(domain, username string) string {
+	return strings.ToLower(domain) + "\\" + strings.ToLower(username)
+}
@@ -0,0 +1,69 @@
+package utils
+
+import "sort"
+
+// Well-known hashes of the empty password.
+const (
+	BlankNTHash = "31d6cfe0d16ae931b73c59d7e0c089c0"
+	BlankLMHash = "aad3b435b51404eeaad3b435b51404ee"
+	// blankLMHalf is the LM hash of an empty 7-character half; a stored LM hash ending in it means a password of at most 7 characters.
+	blankLMHalf = "aad3b435b51404ee"
+)
+
+// PreflightFindings are the weaknesses visible from a hashlist alone, before any cracking.
+type PreflightFindings struct {
+	BlankNT    []HashlistEntry   // Accounts whose NT hash is the empty password
+	BlankLM    []HashlistEntry   // Accounts whose hash to crack is the LM hash of the empty password
+	StoredLM   []HashlistEntry   // secretsdump accounts with a real LM hash stored
+	ShortLM    int               // Stored LM hashes whose second half is empty (password of at most 7 characters)
+	Duplicates [][]HashlistEntry // Accounts sharing one hash, largest group first
+}
+
+// AnalyzePreflight inspects hashes for blank passwords, stored LM hashes and duplicates. History entries
+// are left out of the duplicate groups since `_history0` normally repeats the current hash.
+func AnalyzePreflight(entries []HashlistEntry) PreflightFindings {
+	var findings PreflightFindings
+	byHash := make(map[string][]HashlistEntry)
+	var hashes []string
+	for _, entry := range entries {
+		switch entry.Hash {
+		case BlankNTHash:
+			findings.BlankNT = append(findings.BlankNT, entry)
+		case BlankLMHash:
+			findings.BlankLM = append(findings.BlankLM, entry)
+		}
+		if entry.LMHash != "" && entry.LMHash != BlankLMHash {
+			findings.StoredLM = append(findings.StoredLM, entry)
+			if entry.LMHash[16:] == blankLMHalf {
+				findings.ShortLM++
+			}
+		}
+
+		if entry.Hash == "" || entry.Account.Kind == AccountHistory {
+			continue
+		}
+		if _, ok := byHash[entry.Hash]; !ok {
+			hashes = append(hashes, entry.Hash)
+		}
+		byHash[entry.Hash] = append(byHash[entry.Hash], entry)
+	}
+
+	for _, hash := range hashes {
+		if len(byHash[hash]) > 1 {
+			findings.Duplicates = append(findings.Duplicates, byHash[hash])
+		}
+	}
+	sort.SliceStable(findings.Duplicates, func(i, j int) bool {
+		return len(findings.Duplicates[i]) > len(findings.Duplicates[j])
+	})
+	return findings
+}
+
+// DuplicateAccounts returns how many accounts share their hash with another account.
+func (f PreflightFindings) DuplicateAccounts() int {
+	total := 0
+	for _, group := range f.Duplicates {
+		total += len(group)
+	}
+	return total
+}
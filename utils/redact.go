@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// RedactionMode controls how plaintext passwords appear in an output.
+type RedactionMode string
+
+const (
+	// RedactFull shows passwords as cracked.
+	RedactFull RedactionMode = "full"
+	// RedactPartial keeps the first and last character and masks the rest, preserving the length.
+	RedactPartial RedactionMode = "partial"
+	// RedactHash replaces passwords with their hash, or a placeholder where no hash is known.
+	RedactHash RedactionMode = "hash"
+)
+
+// redactedPlaceholder stands in for a password in hash-only mode when no hash is available.
+const redactedPlaceholder = "[redacted]"
+
+// ParseRedactionMode parses full, partial or hash.
+func ParseRedactionMode(value string) (RedactionMode, error) {
+	switch mode := RedactionMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case RedactFull, RedactPartial, RedactHash:
+		return mode, nil
+	case "":
+		return RedactFull, nil
+	default:
+		return "", fmt.Errorf("unknown redaction mode %q (use full, partial or hash)", value)
+	}
+}
+
+// ParseRedactionModes parses a redaction spec for several outputs: either a single mode applied to
+// every output, or output=mode pairs such as "md=partial,json=full,terminal=hash". Outputs that are
+// not listed keep full passwords.
+func ParseRedactionModes(spec string) (map[string]RedactionMode, error) {
+	modes := make(map[string]RedactionMode)
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		output, value, found := strings.Cut(item, "=")
+		if !found {
+			output, value = "", item
+		}
+		mode, err := ParseRedactionMode(value)
+		if err != nil {
+			return nil, err
+		}
+		modes[strings.ToLower(strings.TrimSpace(output))] = mode
+	}
+	return modes, nil
+}
+
+// RedactionFor returns the mode for an output from a parsed spec, falling back to the spec-wide mode and then full.
+func RedactionFor(modes map[string]RedactionMode, output string) RedactionMode {
+	if mode, ok := modes[output]; ok {
+		return mode
+	}
+	if mode, ok := modes[""]; ok {
+		return mode
+	}
+	return RedactFull
+}
+
+// Redact returns the password as it should appear under the mode. The hash is shown in hash-only mode.
+func (m RedactionMode) Redact(password, hash string) string {
+	switch m {
+	case RedactPartial:
+		runes := []rune(password)
+		if len(runes) <= 2 {
+			return strings.Repeat("*", len(runes))
+		}
+		return string(runes[0]) + strings.Repeat("*", len(runes)-2) + string(runes[len(runes)-1])
+	case RedactHash:
+		if hash != "" {
+			return hash
+		}
+		return redactedPlaceholder
+	default:
+		return password
+	}
+}
+
+// terminalRedaction masks cracked lines that commands print to the terminal; nil leaves output untouched.
+var terminalRedaction *redactingWriter
+
+// SetTerminalRedaction masks passwords in the `hash:password` lines hashcat prints as it cracks the hashes
+// of entries. Commands started with RunCommand and RunShellCommand afterwards write through the mask.
+func SetTerminalRedaction(mode RedactionMode, entries []HashlistEntry) {
+	if mode == RedactFull || mode == "" {
+		terminalRedaction = nil
+		return
+	}
+	hashes := hashSet(entries)
+	for _, entry := range entries {
+		// LM mode cracks and prints each half of an LM hash separately
+		if entry.LMHash != "" {
+			hashes[entry.LMHash] = struct{}{}
+			hashes[entry.LMHash[:16]] = struct{}{}
+			hashes[entry.LMHash[16:]] = struct{}{}
+		}
+	}
+	terminalRedaction = &redactingWriter{w: os.Stdout, mode: mode, hashes: hashes}
+}
+
+// TerminalOutput returns the writer commands should use for standard output and a function that
+// flushes any partial line once the command exits.
+func TerminalOutput() (io.Writer, func()) {
+	if terminalRedaction == nil {
+		return os.Stdout, func() {}
+	}
+	return terminalRedaction, terminalRedaction.flush
+}
+
+// redactingWriter masks the password of each output line that matches a known hash.
+type redactingWriter struct {
+	w       io.Writer
+	mode    RedactionMode
+	hashes  map[string]struct{}
+	pending []byte
+}
+
+// Write passes complete lines through, masking cracked ones, and holds back a trailing partial line.
+func (rw *redactingWriter) Write(p []byte) (int, error) {
+	rw.pending = append(rw.pending, p...)
+	for {
+		end := bytes.IndexAny(rw.pending, "\r\n")
+		if end < 0 {
+			break
+		}
+		if _, err := io.WriteString(rw.w, rw.redactLine(string(rw.pending[:end]))+string(rw.pending[end])); err != nil {
+			return 0, err
+		}
+		rw.pending = rw.pending[end+1:]
+	}
+	return len(p), nil
+}
+
+// flush writes out a held-back partial line.
+func (rw *redactingWriter) flush() {
+	if len(rw.pending) > 0 {
+		io.WriteString(rw.w, rw.redactLine(string(rw.pending)))
+		rw.pending = nil
+	}
+}
+
+// hexCrackedLine matches a `<hex hash>:<password>` line, whether or not the hash is known.
+var hexCrackedLine = regexp.MustCompile(`^[0-9a-fA-F]{16,}:`)
+
+// redactLine masks the password of a cracked line for a known hash, or of any `<hex>:<text>` line so
+// that unexpected hash formats never reach the terminal in clear. Other lines are returned unchanged.
+func (rw *redactingWriter) redactLine(line string) string {
+	if _, _, ok := matchCrackedLine(line, rw.hashes); ok {
+		separator := strings.LastIndex(line, ":")
+		return line[:separator+1] + rw.mode.Redact(DecodeHexPlain(line[separator+1:]), "")
+	}
+	if prefix := hexCrackedLine.FindString(line); prefix != "" {
+		return prefix + rw.mode.Redact(DecodeHexPlain(line[len(prefix):]), "")
+	}
+	return line
+}
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// AccountKind classifies an account name found in a hashlist.
+type AccountKind int
+
+const (
+	// AccountUser is a regular user or service account.
+	AccountUser AccountKind = iota
+	// AccountMachine is a computer account (name ending in `$`).
+	AccountMachine
+	// AccountBuiltin is a built-in account such as krbtgt or Guest.
+	AccountBuiltin
+	// AccountHistory is a secretsdump `_historyN` password history entry.
+	AccountHistory
+)
+
+// String returns a short lowercase label for the account kind.
+func (k AccountKind) String() string {
+	switch k {
+	case AccountMachine:
+		return "machine"
+	case AccountBuiltin:
+		return "builtin"
+	case AccountHistory:
+		return "history"
+	default:
+		return "user"
+	}
+}
+
+// Account is a normalised account name parsed from the first field of a hashlist line.
+type Account struct {
+	Raw          string      // Account field exactly as it appeared in the hashlist
+	Domain       string      // Domain from DOMAIN\user or user@domain, empty for plain names
+	Username     string      // Bare username without domain, `$` or history suffix
+	Kind         AccountKind // Classification of the account
+	HistoryIndex int         // Index N of a `_historyN` entry, -1 otherwise
+}
+
+// builtinAccounts lists lowercase names of built-in accounts that are never worth using as candidates.
+var builtinAccounts = map[string]struct{}{
+	"krbtgt":             {},
+	"guest":              {},
+	"defaultaccount":     {},
+	"wdagutilityaccount": {},
+}
+
+var (
+	historySuffix = regexp.MustCompile(`(?i)_history(\d+)$`)
+	rodcKrbtgt    = regexp.MustCompile(`(?i)^krbtgt_\d+$`)
+)
+
+// NormalizeUsername parses an account name in UPN (user@domain), down-level (DOMAIN\user)
+// or plain form and classifies it as a user, machine, built-in or history account.
+func NormalizeUsername(raw string) Account {
+	account := Account{Raw: raw, HistoryIndex: -1}
+	name := strings.TrimSpace(raw)
+
+	if idx := strings.LastIndex(name, "\\"); idx >= 0 {
+		account.Domain = name[:idx]
+		name = name[idx+1:]
+	} else if idx := strings.LastIndex(name, "@"); idx > 0 {
+		account.Domain = name[idx+1:]
+		name = name[:idx]
+	}
+
+	if match := historySuffix.FindStringSubmatch(name); match != nil {
+		account.Kind = AccountHistory
+		account.HistoryIndex, _ = strconv.Atoi(match[1])
+		name = name[:len(name)-len(match[0])]
+	}
+
+	if strings.HasSuffix(name, "$") {
+		name = strings.TrimSuffix(name, "$")
+		if account.Kind == AccountUser {
+			account.Kind = AccountMachine
+		}
+	}
+
+	if account.Kind == AccountUser {
+		if _, ok := builtinAccounts[strings.ToLower(name)]; ok || rodcKrbtgt.MatchString(name) {
+			account.Kind = AccountBuiltin
+		}
+	}
+
+	account.Username = name
+	return account
+}
+
+// BaseWords splits the username on dots, underscores, hyphens and digits and returns
+// the lowercase alphabetic parts of at least three characters, plus the parts joined together.
+func (a Account) BaseWords() []string {
+	parts := strings.FieldsFunc(strings.ToLower(a.Username), func(r rune) bool {
+		return r == '.' || r == '_' || r == '-' || unicode.IsDigit(r) || unicode.IsSpace(r)
+	})
+
+	var words []string
+	for _, part := range parts {
+		if len([]rune(part)) >= 3 {
+			words = append(words, part)
+		}
+	}
+	if len(parts) > 1 {
+		words = append(words, strings.Join(parts, ""))
+	}
+	return words
+}
+
+// UsernameCandidates builds a deduplicated username wordlist from regular user accounts,
+// adding each account's base words after its full username.
+func UsernameCandidates(accounts []Account) []string {
+	seen := make(map[string]struct{})
+	var candidates []string
+	add := func(word string) {
+		if word == "" {
+			return
+		}
+		if _, ok := seen[word]; ok {
+			return
+		}
+		seen[word] = struct{}{}
+		candidates = append(candidates, word)
+	}
+
+	for _, account := range accounts {
+		if account.Kind != AccountUser {
+			continue
+		}
+		add(account.Username)
+		for _, word := range account.BaseWords() {
+			add(word)
+		}
+	}
+	return candidates
+}
+
+// ParseDomainMap parses NETBIOS=fqdn pairs such as "CORP=corp.local,LAB=lab.corp.local" into a map keyed by
+// uppercase NetBIOS name. A single value without "=" is used as the domain for every account (key "").
+func ParseDomainMap(value string) map[string]string {
+	domains := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		if netbios, fqdn, found := strings.Cut(pair, "="); found {
+			domains[strings.ToUpper(strings.TrimSpace(netbios))] = strings.TrimSpace(fqdn)
+		} else {
+			domains[""] = pair
+		}
+	}
+	return domains
+}
+
+// FQDN returns the account's DNS domain, resolving NetBIOS names through domains (see ParseDomainMap).
+// It falls back to the domain as written in the hashlist.
+func (a Account) FQDN(domains map[string]string) string {
+	if strings.Contains(a.Domain, ".") {
+		return a.Domain
+	}
+	if fqdn, ok := domains[strings.ToUpper(a.Domain)]; ok {
+		return fqdn
+	}
+	if fqdn, ok := domains[""]; ok {
+		return fqdn
+	}
+	return a.Domain
+}
+
+// PrincipalName returns the account's BloodHound principal name: USER@DOMAIN.FQDN for users and
+// HOST.DOMAIN.FQDN for computer accounts, in uppercase.
+func (a Account) PrincipalName(domains map[string]string) string {
+	fqdn := a.FQDN(domains)
+	if a.Kind == AccountMachine {
+		if fqdn == "" {
+			return strings.ToUpper(a.Username)
+		}
+		return strings.ToUpper(a.Username + "." + fqdn)
+	}
+	if fqdn == "" {
+		return strings.ToUpper(a.Username)
+	}
+	return strings.ToUpper(a.Username + "@" + fqdn)
+}